@@ -0,0 +1,37 @@
+# (monitoring ∪ bastions) − decommissioned
+data "nblists_set" "allowed" {
+  operation = "difference"
+  inputs = [
+    { list = data.nblists_set.mgmt.list },
+    {
+      endpoint = "ip-addresses"
+      filter = {
+        tag = ["decommissioned"]
+      }
+    },
+  ]
+  split_af = true
+}
+
+data "nblists_set" "mgmt" {
+  operation = "union"
+  inputs = [
+    {
+      endpoint = "ip-addresses"
+      filter = {
+        tag = ["monitoring"]
+      }
+    },
+    {
+      endpoint = "ip-addresses"
+      filter = {
+        tag = ["bastions"]
+      }
+    },
+  ]
+}
+
+# Use the data
+resource "some_resource" "r" {
+  cidrs = data.nblists_set.allowed.list4
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// baseline is the content of a baseline file.
+type baseline struct {
+	List []string `json:"list"`
+}
+
+// readBaseline reads the list stored in path.
+// The returned bool is false if the file does not exist.
+func readBaseline(path string) ([]string, bool, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var bl baseline
+	if err := json.Unmarshal(b, &bl); err != nil {
+		return nil, false, fmt.Errorf("error decoding %q: %w", path, err)
+	}
+	return bl.List, true, nil
+}
+
+// writeBaseline atomically replaces the list stored in path with list.
+func writeBaseline(path string, list []string) error {
+	b, err := json.MarshalIndent(baseline{List: list}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return errors.Join(err, f.Close(), os.Remove(tmp))
+	}
+	if err := f.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
+
+// diffLists returns the sorted elements of list that are not in base (added)
+// and the sorted elements of base that are not in list (removed).
+func diffLists(base []string, list []string) ([]string, []string) {
+	inBase := make(map[string]bool, len(base))
+	for _, e := range base {
+		inBase[e] = true
+	}
+	inList := make(map[string]bool, len(list))
+	for _, e := range list {
+		inList[e] = true
+	}
+
+	added := []string{}
+	for e := range inList {
+		if !inBase[e] {
+			added = append(added, e)
+		}
+	}
+	removed := []string{}
+	for e := range inBase {
+		if !inList[e] {
+			removed = append(removed, e)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// changePercent returns the number of changes as a percentage of the
+// size of the baseline. Any change to an empty baseline is 100%.
+func changePercent(baseLen int, changes int) float64 {
+	if changes == 0 {
+		return 0
+	}
+	if baseLen == 0 {
+		return 100
+	}
+	return float64(changes) * 100 / float64(baseLen)
+}
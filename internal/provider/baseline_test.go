@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	if _, exists, err := readBaseline(path); err != nil || exists {
+		t.Fatalf("expected a missing baseline, got exists=%v err=%v", exists, err)
+	}
+
+	want := []string{"192.0.2.1/32", "2001:db8::/32"}
+	if err := writeBaseline(path, want); err != nil {
+		t.Fatalf("error writing baseline: %v", err)
+	}
+	have, exists, err := readBaseline(path)
+	if err != nil || !exists {
+		t.Fatalf("expected a baseline, got exists=%v err=%v", exists, err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %v, want %v", have, want)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the baseline in the directory, got %d files", len(entries))
+	}
+}
+
+func TestWriteBaselineError(t *testing.T) {
+	dir := t.TempDir()
+	// Renaming a file over a non-empty directory fails.
+	path := filepath.Join(dir, "baseline.json")
+	if err := os.MkdirAll(filepath.Join(path, "child"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeBaseline(path, []string{"192.0.2.1/32"}); err == nil {
+		t.Fatalf("expected an error")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected the temporary file to be removed, got %d files", len(entries))
+	}
+}
+
+func TestReadBaselineInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte("192.0.2.1/32\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readBaseline(path); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestDiffLists(t *testing.T) {
+	tests := map[string]struct {
+		base        []string
+		list        []string
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		"unchanged": {
+			base:        []string{"192.0.2.1/32", "192.0.2.2/32"},
+			list:        []string{"192.0.2.1/32", "192.0.2.2/32"},
+			wantAdded:   []string{},
+			wantRemoved: []string{},
+		},
+		"empty baseline": {
+			list:        []string{"192.0.2.2/32", "192.0.2.1/32"},
+			wantAdded:   []string{"192.0.2.1/32", "192.0.2.2/32"},
+			wantRemoved: []string{},
+		},
+		"changed": {
+			base:        []string{"192.0.2.1/32", "192.0.2.2/32", "192.0.2.3/32"},
+			list:        []string{"192.0.2.4/32", "192.0.2.2/32"},
+			wantAdded:   []string{"192.0.2.4/32"},
+			wantRemoved: []string{"192.0.2.1/32", "192.0.2.3/32"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			added, removed := diffLists(tc.base, tc.list)
+			if !reflect.DeepEqual(added, tc.wantAdded) {
+				t.Errorf("got added %v, want %v", added, tc.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, tc.wantRemoved) {
+				t.Errorf("got removed %v, want %v", removed, tc.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestChangePercent(t *testing.T) {
+	tests := []struct {
+		baseLen int
+		changes int
+		want    float64
+	}{
+		{baseLen: 400, changes: 0, want: 0},
+		{baseLen: 400, changes: 397, want: 99.25},
+		{baseLen: 4, changes: 8, want: 200},
+		{baseLen: 0, changes: 0, want: 0},
+		{baseLen: 0, changes: 3, want: 100},
+	}
+
+	for _, tc := range tests {
+		if have := changePercent(tc.baseLen, tc.changes); have != tc.want {
+			t.Errorf("changePercent(%d, %d): got %v, want %v", tc.baseLen, tc.changes, have, tc.want)
+		}
+	}
+}
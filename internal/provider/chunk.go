@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+)
+
+// chunkEntries splits entries into chunks of at most size entries, keyed by
+// the address range of the chunk.
+//
+// Chunks are assigned by address range: the address space of each family
+// is halved recursively until every range holds at most size entries, and
+// each non-empty range becomes a chunk keyed by the range in CIDR notation.
+// An entry belongs to the range containing its first address. Adding or
+// removing an entry only changes the chunk of the range it falls in, which
+// is split or merged with its neighbours when it no longer fits or becomes
+// small enough; the keys and contents of all other chunks stay the same.
+// A chunk never mixes IPv4 and IPv6 entries.
+func chunkEntries(entries []listEntry, size int) map[string][]string {
+	sorted := make([]listEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return comparePrefixes(sorted[i].prefix.Masked(), sorted[j].prefix.Masked()) < 0
+	})
+
+	// IPv4 addresses sort before IPv6 addresses.
+	i := sort.Search(len(sorted), func(i int) bool {
+		return !sorted[i].prefix.Addr().Is4()
+	})
+
+	ret := map[string][]string{}
+	chunkRange(ret, sorted[:i], netip.PrefixFrom(netip.IPv4Unspecified(), 0), size)
+	chunkRange(ret, sorted[i:], netip.PrefixFrom(netip.IPv6Unspecified(), 0), size)
+	return ret
+}
+
+// chunkRange adds the chunks of entries to chunks.
+// entries must be sorted and their first addresses must be within r.
+func chunkRange(chunks map[string][]string, entries []listEntry, r netip.Prefix, size int) {
+	if len(entries) == 0 {
+		return
+	}
+	if len(entries) <= size {
+		chunks[r.String()] = entryStrings(entries)
+		return
+	}
+	if r.IsSingleIP() {
+		// The entries share the same address and can't be split any further.
+		// The chunks after the first are numbered.
+		for n := 0; len(entries) > 0; n++ {
+			key := r.String()
+			if n > 0 {
+				key = fmt.Sprintf("%s#%d", r, n)
+			}
+			k := min(size, len(entries))
+			chunks[key] = entryStrings(entries[:k])
+			entries = entries[k:]
+		}
+		return
+	}
+
+	i := sort.Search(len(entries), func(i int) bool {
+		return addrBit(entries[i].prefix.Masked().Addr(), r.Bits())
+	})
+	chunkRange(chunks, entries[:i], netip.PrefixFrom(r.Addr(), r.Bits()+1), size)
+	chunkRange(chunks, entries[i:], netip.PrefixFrom(flipBit(r.Addr(), r.Bits()), r.Bits()+1), size)
+}
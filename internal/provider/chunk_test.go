@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestChunkEntries(t *testing.T) {
+	tests := map[string]struct {
+		in   []string
+		size int
+		want map[string][]string
+	}{
+		"empty": {
+			in:   []string{},
+			size: 2,
+			want: map[string][]string{},
+		},
+		"fits": {
+			in:   []string{"192.0.2.1/32", "192.0.2.2/32"},
+			size: 2,
+			want: map[string][]string{"0.0.0.0/0": {"192.0.2.1/32", "192.0.2.2/32"}},
+		},
+		"families are separate": {
+			in:   []string{"2001:db8::1/128", "192.0.2.1/32"},
+			size: 2,
+			want: map[string][]string{"0.0.0.0/0": {"192.0.2.1/32"}, "::/0": {"2001:db8::1/128"}},
+		},
+		"split by address range": {
+			in:   []string{"10.0.0.1", "10.0.0.2", "192.0.2.1", "192.0.2.2", "198.51.100.1"},
+			size: 2,
+			want: map[string][]string{
+				"0.0.0.0/1":   {"10.0.0.1", "10.0.0.2"},
+				"192.0.0.0/6": {"192.0.2.1", "192.0.2.2"},
+				"196.0.0.0/6": {"198.51.100.1"},
+			},
+		},
+		"same address": {
+			in:   []string{"10.0.0.0/8", "10.0.0.0/16", "10.0.0.0/24"},
+			size: 2,
+			want: map[string][]string{"10.0.0.0/32": {"10.0.0.0/8", "10.0.0.0/16"}, "10.0.0.0/32#1": {"10.0.0.0/24"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if have := chunkEntries(entries, tc.size); !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestChunkEntriesChurn(t *testing.T) {
+	var list []string
+	for i := 0; i < 500; i++ {
+		list = append(list, fmt.Sprintf("10.%d.%d.0/24", (i*37)%256, (i*101)%256))
+	}
+	entries, err := parseEntries(list)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	const size = 60
+	before := chunkEntries(entries, size)
+
+	for i := range entries {
+		// Remove one entry and check that all chunks but the one of its
+		// range kept their key and contents.
+		removed := append(append([]listEntry{}, entries[:i]...), entries[i+1:]...)
+		after := chunkEntries(removed, size)
+		changed := 0
+		for key, c := range after {
+			if len(c) > size {
+				t.Fatalf("chunk has %d entries, want at most %d", len(c), size)
+			}
+			if !reflect.DeepEqual(before[key], c) {
+				changed++
+			}
+		}
+		if changed > 1 {
+			t.Errorf("removing %s changed %d chunks", entries[i].String(), changed)
+		}
+	}
+}
+
+func TestChunkEntriesInsert(t *testing.T) {
+	entries, err := parseEntries([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.128.0.1", "10.128.0.2", "200.0.0.1"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	before := chunkEntries(entries, 3)
+
+	inserted, err := parseEntries([]string{"10.0.0.0"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	after := chunkEntries(append(inserted, entries...), 3)
+
+	for key, c := range before {
+		if key == "10.0.0.0/9" {
+			// The chunk the entry was inserted in.
+			continue
+		}
+		if !reflect.DeepEqual(after[key], c) {
+			t.Errorf("chunk %s changed from %v to %v", key, c, after[key])
+		}
+	}
+	if _, ok := after["10.0.0.0/9"]; ok {
+		t.Errorf("expected chunk 10.0.0.0/9 to be split but got %v", after)
+	}
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// parsePrefix parses s as a prefix. Addresses without a prefix length
+// are treated as single IP prefixes (/32 or /128).
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	a = a.WithZone("")
+	return netip.PrefixFrom(a, a.BitLen()), nil
+}
+
+// prefixStrings returns each prefix in CIDR notation.
+func prefixStrings(prefixes []netip.Prefix) []string {
+	ret := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		ret = append(ret, p.String())
+	}
+	return ret
+}
+
+// comparePrefixes orders prefixes by address family, then address and
+// finally by prefix length.
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// sortPrefixes sorts prefixes using comparePrefixes.
+func sortPrefixes(prefixes []netip.Prefix) {
+	sort.Slice(prefixes, func(i, j int) bool {
+		return comparePrefixes(prefixes[i], prefixes[j]) < 0
+	})
+}
+
+// lastAddr returns the last address in p.
+func lastAddr(p netip.Prefix) netip.Addr {
+	p = p.Masked()
+	a := p.Addr()
+	for i := p.Bits(); i < a.BitLen(); i++ {
+		a = flipBit(a, i)
+	}
+	return a
+}
+
+// prefixNetmask returns the netmask of p as an address,
+// e.g. 255.255.255.0 for a /24.
+func prefixNetmask(p netip.Prefix) netip.Addr {
+	b := make([]byte, p.Addr().BitLen()/8)
+	for i := 0; i < p.Bits(); i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	a, _ := netip.AddrFromSlice(b)
+	return a
+}
+
+// prefixHostmask returns the hostmask (wildcard mask) of p as an address,
+// e.g. 0.0.0.255 for a /24.
+func prefixHostmask(p netip.Prefix) netip.Addr {
+	b := prefixNetmask(p).AsSlice()
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	a, _ := netip.AddrFromSlice(b)
+	return a
+}
+
+// numAddresses returns the number of addresses in p.
+func numAddresses(p netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(p.Addr().BitLen()-p.Bits()))
+}
+
+// rangePrefixes returns the minimal sorted list of prefixes covering the
+// addresses from start to end inclusive. start and end must be of the same
+// family and start must not be greater than end.
+func rangePrefixes(start, end netip.Addr) []netip.Prefix {
+	var ret []netip.Prefix
+	for {
+		// Find the largest prefix starting at start that ends at or before end.
+		p := netip.PrefixFrom(start, start.BitLen())
+		for bits := 0; bits <= start.BitLen(); bits++ {
+			c := netip.PrefixFrom(start, bits)
+			if c.Masked().Addr() == start && lastAddr(c).Compare(end) <= 0 {
+				p = c
+				break
+			}
+		}
+		ret = append(ret, p)
+		start = lastAddr(p).Next()
+		if !start.IsValid() || start.Compare(end) > 0 {
+			return ret
+		}
+	}
+}
+
+// addrRange is an inclusive range of addresses.
+type addrRange struct {
+	start netip.Addr
+	end   netip.Addr
+}
+
+func (r addrRange) String() string {
+	return r.start.String() + "-" + r.end.String()
+}
+
+// prefixRanges returns the minimal sorted list of ranges covering the same
+// address space as prefixes.
+func prefixRanges(prefixes []netip.Prefix) []addrRange {
+	ret := []addrRange{}
+	for _, p := range aggregatePrefixes(prefixes) {
+		if n := len(ret); n > 0 && ret[n-1].end.Next() == p.Addr() {
+			ret[n-1].end = lastAddr(p)
+			continue
+		}
+		ret = append(ret, addrRange{start: p.Addr(), end: lastAddr(p)})
+	}
+	return ret
+}
+
+// expandPrefix returns every address in p.
+func expandPrefix(p netip.Prefix) []netip.Addr {
+	p = p.Masked()
+	ret := []netip.Addr{}
+	for a := p.Addr(); a.IsValid() && p.Contains(a); a = a.Next() {
+		ret = append(ret, a)
+	}
+	return ret
+}
+
+// flipBit returns a with bit i (counting from the most significant bit) flipped.
+func flipBit(a netip.Addr, i int) netip.Addr {
+	if a.Is4() {
+		b := a.As4()
+		b[i/8] ^= 0x80 >> (i % 8)
+		return netip.AddrFrom4(b)
+	}
+	b := a.As16()
+	b[i/8] ^= 0x80 >> (i % 8)
+	return netip.AddrFrom16(b)
+}
+
+// addrBit returns true if bit i (counting from the most significant bit) of a is set.
+func addrBit(a netip.Addr, i int) bool {
+	b := a.AsSlice()
+	return b[i/8]&(0x80>>(i%8)) != 0
+}
+
+// siblingPrefix returns the other half of the parent of p.
+// p must be masked and have a non-zero prefix length.
+func siblingPrefix(p netip.Prefix) netip.Prefix {
+	return netip.PrefixFrom(flipBit(p.Addr(), p.Bits()-1), p.Bits())
+}
+
+// aggregatePrefixes returns the minimal sorted list of prefixes covering
+// the same address space as prefixes.
+func aggregatePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := make([]netip.Prefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		sorted = append(sorted, p.Masked())
+	}
+	sortPrefixes(sorted)
+
+	ret := make([]netip.Prefix, 0, len(sorted))
+	for _, p := range sorted {
+		// Sorting places a covering prefix before the prefixes it contains.
+		if len(ret) > 0 && ret[len(ret)-1].Overlaps(p) {
+			continue
+		}
+		ret = append(ret, p)
+		// Merge siblings into their parent for as long as possible.
+		for len(ret) >= 2 {
+			a, b := ret[len(ret)-2], ret[len(ret)-1]
+			if a.Bits() != b.Bits() || a.Bits() == 0 || siblingPrefix(a) != b {
+				break
+			}
+			parent, _ := a.Addr().Prefix(a.Bits() - 1)
+			ret = append(ret[:len(ret)-2], parent)
+		}
+	}
+	return ret
+}
+
+// overlappingPrefixes returns the prefixes in sorted that overlap p.
+// sorted must be the output of aggregatePrefixes.
+func overlappingPrefixes(sorted []netip.Prefix, p netip.Prefix) []netip.Prefix {
+	p = p.Masked()
+	last := lastAddr(p)
+	i := sort.Search(len(sorted), func(i int) bool {
+		return lastAddr(sorted[i]).Compare(p.Addr()) >= 0
+	})
+	j := i
+	for j < len(sorted) && sorted[j].Addr().Compare(last) <= 0 {
+		j++
+	}
+	return sorted[i:j]
+}
+
+// excludePrefix returns the minimal list of prefixes covering p but not e.
+func excludePrefix(p, e netip.Prefix) []netip.Prefix {
+	p, e = p.Masked(), e.Masked()
+	if !p.Overlaps(e) {
+		return []netip.Prefix{p}
+	}
+	if e.Bits() <= p.Bits() {
+		return nil
+	}
+	ret := make([]netip.Prefix, 0, e.Bits()-p.Bits())
+	for bits := p.Bits() + 1; bits <= e.Bits(); bits++ {
+		inner, _ := e.Addr().Prefix(bits)
+		ret = append(ret, siblingPrefix(inner))
+	}
+	return ret
+}
+
+// excludePrefixes removes the address space of exclude from p.
+// exclude must be the output of aggregatePrefixes.
+func excludePrefixes(p netip.Prefix, exclude []netip.Prefix) []netip.Prefix {
+	ret := []netip.Prefix{p.Masked()}
+	for _, e := range overlappingPrefixes(exclude, p) {
+		next := make([]netip.Prefix, 0, len(ret))
+		for _, r := range ret {
+			next = append(next, excludePrefix(r, e)...)
+		}
+		ret = next
+	}
+	return ret
+}
+
+// subtractPrefixes returns the sorted list of prefixes covering the address
+// space of prefixes but not exclude.
+func subtractPrefixes(prefixes []netip.Prefix, exclude []netip.Prefix) []netip.Prefix {
+	exclude = aggregatePrefixes(exclude)
+	ret := make([]netip.Prefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		ret = append(ret, excludePrefixes(p, exclude)...)
+	}
+	sortPrefixes(ret)
+	return ret
+}
+
+// intersectPrefixes returns the minimal sorted list of prefixes covering
+// the address space common to a and b.
+func intersectPrefixes(a, b []netip.Prefix) []netip.Prefix {
+	a, b = aggregatePrefixes(a), aggregatePrefixes(b)
+	ret := []netip.Prefix{}
+	for _, p := range a {
+		// Aggregated prefixes overlap only if one contains the other.
+		for _, q := range overlappingPrefixes(b, p) {
+			if q.Bits() < p.Bits() {
+				ret = append(ret, p)
+			} else {
+				ret = append(ret, q)
+			}
+		}
+	}
+	return aggregatePrefixes(ret)
+}
@@ -0,0 +1,345 @@
+package provider
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func mustParsePrefixes(t *testing.T, list []string) []netip.Prefix {
+	t.Helper()
+	ret := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		p, err := parsePrefix(s)
+		if err != nil {
+			t.Fatalf("error parsing %q: %v", s, err)
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := map[string]struct {
+		want      string
+		wantError bool
+	}{
+		"192.0.2.1":      {want: "192.0.2.1/32"},
+		"192.0.2.0/24":   {want: "192.0.2.0/24"},
+		"2001:db8::1":    {want: "2001:db8::1/128"},
+		"fe80::1%eth0":   {want: "fe80::1/128"},
+		"2001:db8::/32":  {want: "2001:db8::/32"},
+		"192.0.2.1/33":   {wantError: true},
+		"not an address": {wantError: true},
+	}
+
+	for s, tc := range tests {
+		t.Run(s, func(t *testing.T) {
+			have, err := parsePrefix(s)
+			if err == nil && tc.wantError {
+				t.Fatalf("expected an error")
+			} else if err != nil && !tc.wantError {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if !tc.wantError && have.String() != tc.want {
+				t.Errorf("got %s, want %s", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestLastAddr(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.0/24":    "192.0.2.255",
+		"192.0.2.5/24":    "192.0.2.255",
+		"192.0.2.1/32":    "192.0.2.1",
+		"0.0.0.0/0":       "255.255.255.255",
+		"2001:db8::/64":   "2001:db8::ffff:ffff:ffff:ffff",
+		"2001:db8::1/128": "2001:db8::1",
+	}
+
+	for s, want := range tests {
+		t.Run(s, func(t *testing.T) {
+			if have := lastAddr(netip.MustParsePrefix(s)).String(); have != want {
+				t.Errorf("got %s, want %s", have, want)
+			}
+		})
+	}
+}
+
+func TestAggregatePrefixes(t *testing.T) {
+	tests := map[string]struct {
+		in   []string
+		want []string
+	}{
+		"empty": {
+			in:   []string{},
+			want: []string{},
+		},
+		"siblings": {
+			in:   []string{"192.0.2.1/32", "192.0.2.0/32", "192.0.2.2/31"},
+			want: []string{"192.0.2.0/30"},
+		},
+		"nested": {
+			in:   []string{"192.0.2.5/32", "192.0.2.0/24", "192.0.2.128/25"},
+			want: []string{"192.0.2.0/24"},
+		},
+		"duplicates": {
+			in:   []string{"192.0.2.1", "192.0.2.1/32"},
+			want: []string{"192.0.2.1/32"},
+		},
+		"host bits": {
+			in:   []string{"192.0.2.5/24"},
+			want: []string{"192.0.2.0/24"},
+		},
+		"not siblings": {
+			in:   []string{"192.0.2.1/32", "192.0.2.2/32"},
+			want: []string{"192.0.2.1/32", "192.0.2.2/32"},
+		},
+		"mixed families": {
+			in:   []string{"2001:db8::/33", "2001:db8:8000::/33", "198.51.100.0/24", "192.0.2.0/24"},
+			want: []string{"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"},
+		},
+		"cascading merge": {
+			in:   []string{"10.0.0.0/25", "10.0.0.128/26", "10.0.0.192/26", "10.0.1.0/24"},
+			want: []string{"10.0.0.0/23"},
+		},
+		"default routes": {
+			in:   []string{"0.0.0.0/1", "128.0.0.0/1", "::/0"},
+			want: []string{"0.0.0.0/0", "::/0"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := prefixStrings(aggregatePrefixes(mustParsePrefixes(t, tc.in)))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestSubtractPrefixes(t *testing.T) {
+	tests := map[string]struct {
+		in      []string
+		exclude []string
+		want    []string
+	}{
+		"no overlap": {
+			in:      []string{"192.0.2.0/24"},
+			exclude: []string{"198.51.100.0/24"},
+			want:    []string{"192.0.2.0/24"},
+		},
+		"covered": {
+			in:      []string{"192.0.2.5/32", "192.0.2.64/26"},
+			exclude: []string{"192.0.2.0/24"},
+			want:    []string{},
+		},
+		"equal": {
+			in:      []string{"192.0.2.0/24"},
+			exclude: []string{"192.0.2.0/24"},
+			want:    []string{},
+		},
+		"single IP": {
+			in:      []string{"192.0.2.0/24"},
+			exclude: []string{"192.0.2.5"},
+			want: []string{
+				"192.0.2.0/30",
+				"192.0.2.4/32",
+				"192.0.2.6/31",
+				"192.0.2.8/29",
+				"192.0.2.16/28",
+				"192.0.2.32/27",
+				"192.0.2.64/26",
+				"192.0.2.128/25",
+			},
+		},
+		"lab inside /22": {
+			in:      []string{"10.20.0.0/22"},
+			exclude: []string{"10.20.2.64/26"},
+			want: []string{
+				"10.20.0.0/23",
+				"10.20.2.0/26",
+				"10.20.2.128/25",
+				"10.20.3.0/24",
+			},
+		},
+		"multiple exclusions": {
+			in:      []string{"192.0.2.0/24"},
+			exclude: []string{"192.0.2.0/26", "192.0.2.192/26"},
+			want:    []string{"192.0.2.64/26", "192.0.2.128/26"},
+		},
+		"nested exclusions": {
+			in:      []string{"192.0.2.0/24"},
+			exclude: []string{"192.0.2.128/25", "192.0.2.130/32"},
+			want:    []string{"192.0.2.0/25"},
+		},
+		"IPv6": {
+			in:      []string{"2001:db8::/32"},
+			exclude: []string{"2001:db8:8000::/33", "2001:db8::/34"},
+			want:    []string{"2001:db8:4000::/34"},
+		},
+		"families are independent": {
+			in:      []string{"0.0.0.0/0", "2001:db8::1/128"},
+			exclude: []string{"::/0"},
+			want:    []string{"0.0.0.0/0"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := prefixStrings(subtractPrefixes(mustParsePrefixes(t, tc.in), mustParsePrefixes(t, tc.exclude)))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntersectPrefixes(t *testing.T) {
+	tests := map[string]struct {
+		a    []string
+		b    []string
+		want []string
+	}{
+		"disjoint": {
+			a:    []string{"192.0.2.0/24"},
+			b:    []string{"198.51.100.0/24"},
+			want: []string{},
+		},
+		"contained": {
+			a:    []string{"192.0.2.0/24", "2001:db8::/32"},
+			b:    []string{"192.0.2.5", "192.0.2.128/25", "2001:db8::/64"},
+			want: []string{"192.0.2.5/32", "192.0.2.128/25", "2001:db8::/64"},
+		},
+		"containing": {
+			a:    []string{"192.0.2.5/32"},
+			b:    []string{"0.0.0.0/0"},
+			want: []string{"192.0.2.5/32"},
+		},
+		"aggregated": {
+			a:    []string{"192.0.2.0/25", "192.0.2.128/25"},
+			b:    []string{"192.0.2.0/26", "192.0.2.64/26", "198.51.100.0/24"},
+			want: []string{"192.0.2.0/25"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := prefixStrings(intersectPrefixes(mustParsePrefixes(t, tc.a), mustParsePrefixes(t, tc.b)))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrefixMasks(t *testing.T) {
+	tests := map[string]struct {
+		netmask      string
+		hostmask     string
+		numAddresses string
+	}{
+		"192.0.2.0/24":    {netmask: "255.255.255.0", hostmask: "0.0.0.255", numAddresses: "256"},
+		"192.0.2.0/27":    {netmask: "255.255.255.224", hostmask: "0.0.0.31", numAddresses: "32"},
+		"192.0.2.1/32":    {netmask: "255.255.255.255", hostmask: "0.0.0.0", numAddresses: "1"},
+		"0.0.0.0/0":       {netmask: "0.0.0.0", hostmask: "255.255.255.255", numAddresses: "4294967296"},
+		"2001:db8::/64":   {netmask: "ffff:ffff:ffff:ffff::", hostmask: "::ffff:ffff:ffff:ffff", numAddresses: "18446744073709551616"},
+		"2001:db8::1/128": {netmask: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", hostmask: "::", numAddresses: "1"},
+		"::/0":            {netmask: "::", hostmask: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", numAddresses: "340282366920938463463374607431768211456"},
+	}
+
+	for s, tc := range tests {
+		t.Run(s, func(t *testing.T) {
+			p := netip.MustParsePrefix(s)
+			if have := prefixNetmask(p).String(); have != tc.netmask {
+				t.Errorf("got netmask %s, want %s", have, tc.netmask)
+			}
+			if have := prefixHostmask(p).String(); have != tc.hostmask {
+				t.Errorf("got hostmask %s, want %s", have, tc.hostmask)
+			}
+			if have := numAddresses(p).String(); have != tc.numAddresses {
+				t.Errorf("got %s addresses, want %s", have, tc.numAddresses)
+			}
+		})
+	}
+}
+
+func TestRangePrefixes(t *testing.T) {
+	tests := map[string]struct {
+		start string
+		end   string
+		want  []string
+	}{
+		"single IP":  {start: "192.0.2.1", end: "192.0.2.1", want: []string{"192.0.2.1/32"}},
+		"aligned":    {start: "192.0.2.0", end: "192.0.2.255", want: []string{"192.0.2.0/24"}},
+		"unaligned":  {start: "192.0.2.10", end: "192.0.2.20", want: []string{"192.0.2.10/31", "192.0.2.12/30", "192.0.2.16/30", "192.0.2.20/32"}},
+		"everything": {start: "0.0.0.0", end: "255.255.255.255", want: []string{"0.0.0.0/0"}},
+		"end of address space": {
+			start: "255.255.255.254",
+			end:   "255.255.255.255",
+			want:  []string{"255.255.255.254/31"},
+		},
+		"IPv6": {start: "2001:db8::1", end: "2001:db8::ff", want: []string{
+			"2001:db8::1/128",
+			"2001:db8::2/127",
+			"2001:db8::4/126",
+			"2001:db8::8/125",
+			"2001:db8::10/124",
+			"2001:db8::20/123",
+			"2001:db8::40/122",
+			"2001:db8::80/121",
+		}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := prefixStrings(rangePrefixes(netip.MustParseAddr(tc.start), netip.MustParseAddr(tc.end)))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrefixRanges(t *testing.T) {
+	tests := map[string]struct {
+		in   []string
+		want []string
+	}{
+		"empty": {
+			in:   []string{},
+			want: []string{},
+		},
+		"adjacent": {
+			in:   []string{"192.0.2.10/31", "192.0.2.12/30", "192.0.2.16/32"},
+			want: []string{"192.0.2.10-192.0.2.16"},
+		},
+		"gap": {
+			in:   []string{"192.0.2.0/30", "192.0.2.5/32"},
+			want: []string{"192.0.2.0-192.0.2.3", "192.0.2.5-192.0.2.5"},
+		},
+		"overlapping": {
+			in:   []string{"192.0.2.0/24", "192.0.2.5/32", "192.0.3.0/25"},
+			want: []string{"192.0.2.0-192.0.3.127"},
+		},
+		"families are not merged": {
+			in:   []string{"255.255.255.255/32", "::/128"},
+			want: []string{"255.255.255.255-255.255.255.255", "::-::"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ranges := prefixRanges(mustParsePrefixes(t, tc.in))
+			have := make([]string, 0, len(ranges))
+			for _, r := range ranges {
+				have = append(have, r.String())
+			}
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+)
+
+// revisionLength is the length of the short fingerprint.
+const revisionLength = 12
+
+// listFingerprint returns the hex encoded SHA-256 of the newline
+// terminated elements of list.
+func listFingerprint(list []string) string {
+	h := sha256.New()
+	for _, e := range list {
+		h.Write([]byte(e))
+		h.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// listID returns a deterministic ID derived from the endpoint,
+// the filter and the fingerprint of the list.
+func listID(endpoint string, filter map[string][]string, list []string) string {
+	canonical := make(url.Values, len(filter))
+	for k, v := range filter {
+		sorted := append([]string{}, v...)
+		sort.Strings(sorted)
+		canonical[k] = sorted
+	}
+	return listFingerprint([]string{endpoint, canonical.Encode(), listFingerprint(list)})
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestListFingerprint(t *testing.T) {
+	tests := map[string]struct {
+		list []string
+		want string
+	}{
+		"empty": {
+			list: []string{},
+			want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		"one": {
+			list: []string{"192.0.2.1/32"},
+			want: "2a9e60320771724c2e26f04bf3b72c7028987d06a2b5ca38bc902fe109b01c14",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if have := listFingerprint(tc.list); have != tc.want {
+				t.Errorf("got %s, want %s", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestListID(t *testing.T) {
+	list := []string{"192.0.2.1/32", "192.0.2.2/32"}
+	id := listID("ip-addresses", map[string][]string{"tag": {"a", "b"}, "family": {"4"}}, list)
+
+	if have := listID("ip-addresses", map[string][]string{"family": {"4"}, "tag": {"b", "a"}}, list); have != id {
+		t.Errorf("expected the ID to not depend on filter order: got %s, want %s", have, id)
+	}
+	if have := listID("prefixes", map[string][]string{"tag": {"a", "b"}, "family": {"4"}}, list); have == id {
+		t.Errorf("expected the ID to depend on the endpoint")
+	}
+	if have := listID("ip-addresses", map[string][]string{"tag": {"a"}, "family": {"4"}}, list); have == id {
+		t.Errorf("expected the ID to depend on the filter")
+	}
+	if have := listID("ip-addresses", map[string][]string{"tag": {"a", "b"}, "family": {"4"}}, list[:1]); have == id {
+		t.Errorf("expected the ID to depend on the list")
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/netip"
+)
+
+// ipBlock is a CIDR with the prefixes inside it to exclude.
+// It has the shape of a Kubernetes IPBlock and a Cilium CIDRRule.
+type ipBlock struct {
+	CIDR   string   `json:"cidr"`
+	Except []string `json:"except,omitempty"`
+}
+
+// computeIPBlocks returns the minimal set of ipBlocks covering allowed
+// except excluded. Both are aggregated first. Allowed prefixes entirely
+// covered by an excluded prefix are omitted.
+func computeIPBlocks(allowed []netip.Prefix, excluded []netip.Prefix) []ipBlock {
+	excluded = aggregatePrefixes(excluded)
+
+	ret := []ipBlock{}
+	for _, a := range aggregatePrefixes(allowed) {
+		block := ipBlock{CIDR: a.String()}
+		covered := false
+		for _, e := range excluded {
+			switch {
+			case e.Bits() <= a.Bits() && e.Contains(a.Addr()):
+				covered = true
+			case e.Bits() > a.Bits() && a.Contains(e.Addr()):
+				block.Except = append(block.Except, e.String())
+			}
+		}
+		if !covered {
+			ret = append(ret, block)
+		}
+	}
+	return ret
+}
+
+// kubernetesIPBlocksJSON returns blocks as a JSON list of Kubernetes
+// NetworkPolicyPeers.
+func kubernetesIPBlocksJSON(blocks []ipBlock) (string, error) {
+	type peer struct {
+		IPBlock ipBlock `json:"ipBlock"`
+	}
+	peers := make([]peer, 0, len(blocks))
+	for _, b := range blocks {
+		peers = append(peers, peer{IPBlock: b})
+	}
+	b, err := json.Marshal(peers)
+	return string(b), err
+}
+
+// ciliumCIDRSetJSON returns blocks as a JSON list of Cilium CIDRRules as
+// used by toCIDRSet and fromCIDRSet.
+func ciliumCIDRSetJSON(blocks []ipBlock) (string, error) {
+	b, err := json.Marshal(blocks)
+	return string(b), err
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestComputeIPBlocks(t *testing.T) {
+	parse := func(ss ...string) []netip.Prefix {
+		ret := make([]netip.Prefix, 0, len(ss))
+		for _, s := range ss {
+			ret = append(ret, netip.MustParsePrefix(s))
+		}
+		return ret
+	}
+
+	tests := map[string]struct {
+		allowed  []netip.Prefix
+		excluded []netip.Prefix
+		want     []ipBlock
+	}{
+		"except": {
+			allowed:  parse("10.0.0.0/8", "2001:db8::/32"),
+			excluded: parse("10.1.0.0/16", "10.2.0.0/24", "2001:db8:1::/48"),
+			want: []ipBlock{
+				{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16", "10.2.0.0/24"}},
+				{CIDR: "2001:db8::/32", Except: []string{"2001:db8:1::/48"}},
+			},
+		},
+		"aggregated": {
+			allowed:  parse("10.0.0.0/9", "10.128.0.0/9"),
+			excluded: parse("10.1.0.0/17", "10.1.128.0/17"),
+			want:     []ipBlock{{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}},
+		},
+		"covered": {
+			allowed:  parse("10.1.2.0/24", "192.0.2.0/24"),
+			excluded: parse("10.0.0.0/8", "192.0.2.0/24"),
+			want:     []ipBlock{},
+		},
+		"outside": {
+			allowed:  parse("10.0.0.0/8"),
+			excluded: parse("192.0.2.0/24"),
+			want:     []ipBlock{{CIDR: "10.0.0.0/8"}},
+		},
+		"empty": {
+			want: []ipBlock{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := computeIPBlocks(tc.allowed, tc.excluded)
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestIPBlocksJSON(t *testing.T) {
+	blocks := []ipBlock{
+		{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}},
+		{CIDR: "192.0.2.0/24"},
+	}
+
+	have, err := kubernetesIPBlocksJSON(blocks)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	want := `[{"ipBlock":{"cidr":"10.0.0.0/8","except":["10.1.0.0/16"]}},{"ipBlock":{"cidr":"192.0.2.0/24"}}]`
+	if have != want {
+		t.Errorf("expected %s but got %s", want, have)
+	}
+
+	have, err = ciliumCIDRSetJSON(blocks)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	want = `[{"cidr":"10.0.0.0/8","except":["10.1.0.0/16"]},{"cidr":"192.0.2.0/24"}]`
+	if have != want {
+		t.Errorf("expected %s but got %s", want, have)
+	}
+
+	have, err = kubernetesIPBlocksJSON([]ipBlock{})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	if have != "[]" {
+		t.Errorf("expected [] but got %s", have)
+	}
+}
@@ -2,24 +2,61 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/netip"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 )
 
+const (
+	onViolationDrop  = "drop"
+	onViolationWarn  = "warn"
+	onViolationError = "error"
+
+	partialOverlapKeep = "keep"
+	partialOverlapClip = "clip"
+	partialOverlapDrop = "drop"
+
+	maskIPv6Skip  = "skip"
+	maskIPv6CIDR  = "cidr"
+	maskIPv6Error = "error"
+
+	normalizeMaskHostBits = "mask_host_bits"
+	normalizeHostRoute    = "host_route"
+	normalizeUnmapIPv4    = "unmap_ipv4"
+	normalizeCanonical    = "canonical"
+
+	defaultFormatName = "nblists"
+
+	ipBlockModeExceptList     = "except_list"
+	ipBlockModeExceptPrefixes = "except_prefixes"
+
+	defaultExpandMaxPrefixSize = 256
+	defaultExpandMaxTotal      = 65536
+)
+
+var formatNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
 // Ensure provider defined types fully satisfy framework interfaces
 var _ datasource.DataSource = &ListDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &ListDataSource{}
 
 func NewListDataSource() datasource.DataSource {
 	return &ListDataSource{}
@@ -32,20 +69,239 @@ type ListDataSource struct {
 
 // ListDataSourceModel describes the data source data model.
 type ListDataSourceModel struct {
-	Endpoint       types.String `tfsdk:"endpoint"`
-	Filter         types.Map    `tfsdk:"filter"`
-	List           types.List   `tfsdk:"list"`
-	List4          types.List   `tfsdk:"list4"`
-	List6          types.List   `tfsdk:"list6"`
-	ListNoCIDR     types.List   `tfsdk:"list_no_cidr"`
-	AsCIDR         types.Bool   `tfsdk:"as_cidr"`
-	Family         types.Int64  `tfsdk:"family"`
-	NoCIDRSingleIP types.Bool   `tfsdk:"no_cidr_single_ip"`
-	Summarize      types.Bool   `tfsdk:"summarize"`
-	Min            types.Int64  `tfsdk:"min"`
-	Max            types.Int64  `tfsdk:"max"`
-	SplitAF        types.Bool   `tfsdk:"split_af"`
-	ID             types.String `tfsdk:"id"`
+	Endpoint        types.String  `tfsdk:"endpoint"`
+	Filter          types.Map     `tfsdk:"filter"`
+	List            types.List    `tfsdk:"list"`
+	List4           types.List    `tfsdk:"list4"`
+	List6           types.List    `tfsdk:"list6"`
+	ListNoCIDR      types.List    `tfsdk:"list_no_cidr"`
+	AddressRanges   types.Bool    `tfsdk:"address_ranges"`
+	ListRanges      types.List    `tfsdk:"list_ranges"`
+	ReverseDNS      types.Bool    `tfsdk:"reverse_dns"`
+	PTRNames        types.Map     `tfsdk:"ptr_names"`
+	ReverseZones    types.List    `tfsdk:"reverse_zones"`
+	AsCIDR          types.Bool    `tfsdk:"as_cidr"`
+	Family          types.Int64   `tfsdk:"family"`
+	NoCIDRSingleIP  types.Bool    `tfsdk:"no_cidr_single_ip"`
+	MaskNotation    types.Bool    `tfsdk:"mask_notation"`
+	MaskIPv6        types.String  `tfsdk:"mask_notation_ipv6"`
+	ListNetmask     types.List    `tfsdk:"list_netmask"`
+	ListWildcard    types.List    `tfsdk:"list_wildcard"`
+	Summarize       types.Bool    `tfsdk:"summarize"`
+	Min             types.Int64   `tfsdk:"min"`
+	Max             types.Int64   `tfsdk:"max"`
+	Min4            types.Int64   `tfsdk:"min4"`
+	Max4            types.Int64   `tfsdk:"max4"`
+	Min6            types.Int64   `tfsdk:"min6"`
+	Max6            types.Int64   `tfsdk:"max6"`
+	MustContain     types.Set     `tfsdk:"must_contain"`
+	SplitAF         types.Bool    `tfsdk:"split_af"`
+	Normalize       types.Set     `tfsdk:"normalize"`
+	Exclude         types.Set     `tfsdk:"exclude"`
+	Within          types.Set     `tfsdk:"within"`
+	NotWithin       types.Set     `tfsdk:"not_within"`
+	PartialOverlap  types.String  `tfsdk:"partial_overlap"`
+	ExcludeSpecial  types.Set     `tfsdk:"exclude_special"`
+	Special         types.Map     `tfsdk:"special"`
+	DropRedundant   types.Bool    `tfsdk:"drop_redundant"`
+	FindOverlaps    types.Bool    `tfsdk:"find_overlaps"`
+	Overlaps        types.List    `tfsdk:"overlaps"`
+	Redundant       types.List    `tfsdk:"redundant"`
+	MinPrefixLen4   types.Int64   `tfsdk:"min_prefix_length4"`
+	MaxPrefixLen4   types.Int64   `tfsdk:"max_prefix_length4"`
+	MinPrefixLen6   types.Int64   `tfsdk:"min_prefix_length6"`
+	MaxPrefixLen6   types.Int64   `tfsdk:"max_prefix_length6"`
+	OnViolation     types.String  `tfsdk:"on_violation"`
+	EntryDetails    types.Bool    `tfsdk:"entry_details"`
+	Entries         types.List    `tfsdk:"entries"`
+	EntriesByCIDR   types.Map     `tfsdk:"entries_by_cidr"`
+	Expand          types.Bool    `tfsdk:"expand"`
+	ExpandMaxSize   types.Int64   `tfsdk:"expand_max_prefix_size"`
+	ExpandMaxTotal  types.Int64   `tfsdk:"expand_max_total"`
+	ExpandedList    types.List    `tfsdk:"expanded_list"`
+	BaselineFile    types.String  `tfsdk:"baseline_file"`
+	MaxAdded        types.Int64   `tfsdk:"max_added"`
+	MaxRemoved      types.Int64   `tfsdk:"max_removed"`
+	MaxChangePct    types.Float64 `tfsdk:"max_change_percent"`
+	AcceptChanges   types.Bool    `tfsdk:"accept_changes"`
+	Added           types.List    `tfsdk:"added"`
+	Removed         types.List    `tfsdk:"removed"`
+	ChunkSize       types.Int64   `tfsdk:"chunk_size"`
+	Chunks          types.Map     `tfsdk:"chunks"`
+	EntryCount      types.Int64   `tfsdk:"entry_count"`
+	Count4          types.Int64   `tfsdk:"count4"`
+	Count6          types.Int64   `tfsdk:"count6"`
+	TotalAddresses4 types.Int64   `tfsdk:"total_addresses4"`
+	TotalAddresses6 types.String  `tfsdk:"total_addresses6"`
+	LargestPrefix   types.String  `tfsdk:"largest_prefix"`
+	SmallestPrefix  types.String  `tfsdk:"smallest_prefix"`
+	IsEmpty         types.Bool    `tfsdk:"is_empty"`
+	Template        types.String  `tfsdk:"template"`
+	Format          types.String  `tfsdk:"format"`
+	FormatName      types.String  `tfsdk:"format_name"`
+	FormatDenyAll   types.Bool    `tfsdk:"format_deny_all"`
+	RPSLOrigin      types.String  `tfsdk:"rpsl_origin"`
+	RPSLMntBy       types.Set     `tfsdk:"rpsl_mnt_by"`
+	RPSLSource      types.String  `tfsdk:"rpsl_source"`
+	RPSLRouteSet    types.String  `tfsdk:"rpsl_route_set"`
+	PrefixListGe4   types.Int64   `tfsdk:"prefix_list_ge4"`
+	PrefixListLe4   types.Int64   `tfsdk:"prefix_list_le4"`
+	PrefixListGe6   types.Int64   `tfsdk:"prefix_list_ge6"`
+	PrefixListLe6   types.Int64   `tfsdk:"prefix_list_le6"`
+	Rendered        types.String  `tfsdk:"rendered"`
+	IPBlockPrefixes types.Set     `tfsdk:"ip_block_prefixes"`
+	IPBlockMode     types.String  `tfsdk:"ip_block_mode"`
+	IPBlocks        types.String  `tfsdk:"ip_blocks"`
+	CiliumCIDRSet   types.String  `tfsdk:"cilium_cidr_set"`
+	SHA256          types.String  `tfsdk:"sha256"`
+	Revision        types.String  `tfsdk:"revision"`
+	ID              types.String  `tfsdk:"id"`
+}
+
+// entryDetailsAttrTypes are the attribute types of entryDetails.
+var entryDetailsAttrTypes = map[string]attr.Type{
+	"cidr":          types.StringType,
+	"address":       types.StringType,
+	"prefix_length": types.Int64Type,
+	"family":        types.Int64Type,
+	"is_single_ip":  types.BoolType,
+	"network":       types.StringType,
+	"broadcast":     types.StringType,
+	"netmask":       types.StringType,
+	"hostmask":      types.StringType,
+	"num_addresses": types.StringType,
+}
+
+// entryOverlapAttrTypes are the attribute types of entryOverlap.
+var entryOverlapAttrTypes = map[string]attr.Type{
+	"covering": types.StringType,
+	"covered":  types.StringType,
+}
+
+// entryDetailsAttributes are the schema attributes of entryDetails.
+var entryDetailsAttributes = map[string]schema.Attribute{
+	"cidr": schema.StringAttribute{
+		MarkdownDescription: "The entry in CIDR notation.",
+		Computed:            true,
+	},
+	"address": schema.StringAttribute{
+		MarkdownDescription: "The address of the entry without the prefix length.",
+		Computed:            true,
+	},
+	"prefix_length": schema.Int64Attribute{
+		MarkdownDescription: "The prefix length.",
+		Computed:            true,
+	},
+	"family": schema.Int64Attribute{
+		MarkdownDescription: "The address family, `4` or `6`.",
+		Computed:            true,
+	},
+	"is_single_ip": schema.BoolAttribute{
+		MarkdownDescription: "Whether the entry is a single IP (`/32` or `/128`).",
+		Computed:            true,
+	},
+	"network": schema.StringAttribute{
+		MarkdownDescription: "The first address of the prefix.",
+		Computed:            true,
+	},
+	"broadcast": schema.StringAttribute{
+		MarkdownDescription: "The last address of the prefix.",
+		Computed:            true,
+	},
+	"netmask": schema.StringAttribute{
+		MarkdownDescription: "The netmask, e.g. `255.255.255.0`.",
+		Computed:            true,
+	},
+	"hostmask": schema.StringAttribute{
+		MarkdownDescription: "The hostmask (wildcard mask), e.g. `0.0.0.255`.",
+		Computed:            true,
+	},
+	"num_addresses": schema.StringAttribute{
+		MarkdownDescription: "The number of addresses in the prefix as a decimal string.",
+		Computed:            true,
+	},
+}
+
+// needsParsing returns true if the configuration requires the list to be parsed.
+func (m *ListDataSourceModel) needsParsing() bool {
+	return m.SplitAF.ValueBool() ||
+		m.NoCIDRSingleIP.ValueBool() ||
+		m.MaskNotation.ValueBool() ||
+		!m.Min4.IsNull() ||
+		!m.Max4.IsNull() ||
+		!m.Min6.IsNull() ||
+		!m.Max6.IsNull() ||
+		!m.MustContain.IsNull() ||
+		m.DropRedundant.ValueBool() ||
+		m.FindOverlaps.ValueBool() ||
+		m.AddressRanges.ValueBool() ||
+		m.ReverseDNS.ValueBool() ||
+		m.EntryDetails.ValueBool() ||
+		!m.Template.IsNull() ||
+		!m.Format.IsNull() ||
+		!m.Normalize.IsNull() ||
+		!m.Exclude.IsNull() ||
+		!m.Within.IsNull() ||
+		!m.NotWithin.IsNull() ||
+		!m.ExcludeSpecial.IsNull() ||
+		!m.MinPrefixLen4.IsNull() ||
+		!m.MaxPrefixLen4.IsNull() ||
+		!m.MinPrefixLen6.IsNull() ||
+		!m.MaxPrefixLen6.IsNull() ||
+		!m.ChunkSize.IsNull() ||
+		!m.IPBlockPrefixes.IsNull() ||
+		m.Expand.ValueBool()
+}
+
+// ipBlockMode returns the configured ip_block_mode.
+func (m *ListDataSourceModel) ipBlockMode() string {
+	if m.IPBlockMode.IsNull() {
+		return ipBlockModeExceptList
+	}
+	return m.IPBlockMode.ValueString()
+}
+
+// partialOverlap returns the configured partial overlap policy.
+func (m *ListDataSourceModel) partialOverlap() string {
+	if m.PartialOverlap.IsNull() {
+		return partialOverlapDrop
+	}
+	return m.PartialOverlap.ValueString()
+}
+
+// addViolation adds a diagnostic for a failed assertion according to on_violation.
+func (m *ListDataSourceModel) addViolation(diags *diag.Diagnostics, summary string, detail string) {
+	if m.OnViolation.ValueString() == onViolationWarn {
+		diags.AddWarning(summary, detail)
+	} else {
+		diags.AddError(summary, detail)
+	}
+}
+
+// maskIPv6 returns the configured IPv6 policy for mask notation.
+func (m *ListDataSourceModel) maskIPv6() string {
+	if m.MaskIPv6.IsNull() {
+		return maskIPv6Skip
+	}
+	return m.MaskIPv6.ValueString()
+}
+
+// prefixLengthLimits returns the configured prefix length limits.
+func (m *ListDataSourceModel) prefixLengthLimits() prefixLengthLimits {
+	l := prefixLengthLimits{min4: 0, max4: 32, min6: 0, max6: 128}
+	if !m.MinPrefixLen4.IsNull() {
+		l.min4 = int(m.MinPrefixLen4.ValueInt64())
+	}
+	if !m.MaxPrefixLen4.IsNull() {
+		l.max4 = int(m.MaxPrefixLen4.ValueInt64())
+	}
+	if !m.MinPrefixLen6.IsNull() {
+		l.min6 = int(m.MinPrefixLen6.ValueInt64())
+	}
+	if !m.MaxPrefixLen6.IsNull() {
+		l.max6 = int(m.MaxPrefixLen6.ValueInt64())
+	}
+	return l
 }
 
 func (d *ListDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -90,6 +346,28 @@ func (d *ListDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 				MarkdownDescription: "Throw an error if the number of IPs/prefixes is greater than `max`.",
 				Optional:            true,
 			},
+			"min4": schema.Int64Attribute{
+				MarkdownDescription: "Throw an error if the number of IPv4 addresses/prefixes is less than `min4`.",
+				Optional:            true,
+			},
+			"max4": schema.Int64Attribute{
+				MarkdownDescription: "Throw an error if the number of IPv4 addresses/prefixes is greater than `max4`.",
+				Optional:            true,
+			},
+			"min6": schema.Int64Attribute{
+				MarkdownDescription: "Throw an error if the number of IPv6 addresses/prefixes is less than `min6`.",
+				Optional:            true,
+			},
+			"max6": schema.Int64Attribute{
+				MarkdownDescription: "Throw an error if the number of IPv6 addresses/prefixes is greater than `max6`.",
+				Optional:            true,
+			},
+			"must_contain": schema.SetAttribute{
+				MarkdownDescription: "IP addresses/prefixes that must be covered by the entries of `list`. " +
+					"Throw an error listing the missing ones otherwise.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
 			"split_af": schema.BoolAttribute{
 				MarkdownDescription: "Populate `list4` and `list6` with the IPv4 and IPv6 addresses from `list`.",
 				Optional:            true,
@@ -99,16 +377,322 @@ func (d *ListDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 					"Useful for resources whose idempotency breaks when single IPs are in CIDR format.",
 				Optional: true,
 			},
+			"mask_notation": schema.BoolAttribute{
+				MarkdownDescription: "Populates `list_netmask` and `list_wildcard` with the entries of `list` in netmask " +
+					"(`192.0.2.0 255.255.255.0`) and wildcard mask (`192.0.2.0 0.0.0.255`) notation. " +
+					"Useful for legacy network devices that do not accept CIDR notation.",
+				Optional: true,
+			},
+			"mask_notation_ipv6": schema.StringAttribute{
+				MarkdownDescription: "How IPv6 entries are handled in `list_netmask` and `list_wildcard`. " +
+					"`" + maskIPv6Skip + "` omits them, `" + maskIPv6CIDR + "` keeps them in CIDR notation and `" +
+					maskIPv6Error + "` fails. Defaults to `" + maskIPv6Skip + "`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(maskIPv6Skip, maskIPv6CIDR, maskIPv6Error),
+				},
+			},
+			"normalize": schema.SetAttribute{
+				MarkdownDescription: "Normalizations to apply to the entries before any other processing. " +
+					"`" + normalizeMaskHostBits + "` clears the host bits of prefixes (`192.0.2.5/24` becomes `192.0.2.0/24`), " +
+					"`" + normalizeHostRoute + "` replaces prefixes with the host route of their address " +
+					"(`192.0.2.5/24` becomes `192.0.2.5/32`), `" + normalizeUnmapIPv4 + "` converts IPv4-mapped IPv6 " +
+					"addresses to IPv4 (`::ffff:192.0.2.1` becomes `192.0.2.1`) and `" + normalizeCanonical + "` rewrites " +
+					"entries in their canonical text form (RFC 5952 for IPv6). " +
+					"`" + normalizeMaskHostBits + "` and `" + normalizeHostRoute + "` are mutually exclusive.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.OneOf(normalizeMaskHostBits, normalizeHostRoute, normalizeUnmapIPv4, normalizeCanonical),
+					),
+				},
+			},
+			"exclude": schema.SetAttribute{
+				MarkdownDescription: "IP addresses/prefixes to remove from the list. " +
+					"Entries within an excluded prefix are dropped and entries covering an excluded prefix " +
+					"are split into the minimal set of remaining prefixes.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"within": schema.SetAttribute{
+				MarkdownDescription: "Only keep entries within one of these prefixes. " +
+					"Entries covering one of these prefixes are handled according to `partial_overlap`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"not_within": schema.SetAttribute{
+				MarkdownDescription: "Remove entries within one of these prefixes. " +
+					"Entries covering one of these prefixes are handled according to `partial_overlap`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"partial_overlap": schema.StringAttribute{
+				MarkdownDescription: "What to do with entries that partially overlap a prefix of `within` or `not_within`. " +
+					"`" + partialOverlapKeep + "` keeps the entry as is, `" + partialOverlapClip + "` replaces the entry with " +
+					"the part inside `within` or outside `not_within` and `" + partialOverlapDrop + "` removes the entry. " +
+					"Defaults to `" + partialOverlapDrop + "`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(partialOverlapKeep, partialOverlapClip, partialOverlapDrop),
+				},
+			},
+			"exclude_special": schema.SetAttribute{
+				MarkdownDescription: "Special-purpose address categories to remove from the list. " +
+					"Entries within a category are dropped and entries covering a category are split into the remaining prefixes. " +
+					"`" + specialBogon + "` matches all categories. Set to an empty set to only populate `special`. " +
+					"`" + specialTranslation + "` includes NAT64 and 6to4. " +
+					"The globally routed AS112 (`192.31.196.0/24`, `192.175.48.0/24`, `2620:4f:8000::/48`) and AMT (`192.52.193.0/24`) " +
+					"anycast prefixes of the IANA registries are not included. " +
+					"Valid categories are: `" + strings.Join(specialCategories(), "`, `") + "`.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(specialCategories()...)),
+				},
+			},
+			"min_prefix_length4": schema.Int64Attribute{
+				MarkdownDescription: "Minimum prefix length of IPv4 entries. Use to guard against overly broad prefixes such as `0.0.0.0/0`. " +
+					"See `on_violation`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 32),
+				},
+			},
+			"max_prefix_length4": schema.Int64Attribute{
+				MarkdownDescription: "Maximum prefix length of IPv4 entries. See `on_violation`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 32),
+				},
+			},
+			"min_prefix_length6": schema.Int64Attribute{
+				MarkdownDescription: "Minimum prefix length of IPv6 entries. Use to guard against overly broad prefixes such as `::/0`. " +
+					"See `on_violation`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 128),
+				},
+			},
+			"max_prefix_length6": schema.Int64Attribute{
+				MarkdownDescription: "Maximum prefix length of IPv6 entries. See `on_violation`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 128),
+				},
+			},
+			"drop_redundant": schema.BoolAttribute{
+				MarkdownDescription: "Remove entries that are contained in another entry from the list.",
+				Optional:            true,
+			},
+			"find_overlaps": schema.BoolAttribute{
+				MarkdownDescription: "Populate `overlaps` and `redundant`.",
+				Optional:            true,
+			},
+			"address_ranges": schema.BoolAttribute{
+				MarkdownDescription: "Populate `list_ranges`.",
+				Optional:            true,
+			},
+			"reverse_dns": schema.BoolAttribute{
+				MarkdownDescription: "Populate `ptr_names` and `reverse_zones`.",
+				Optional:            true,
+			},
+			"entry_details": schema.BoolAttribute{
+				MarkdownDescription: "Populate `entries` and `entries_by_cidr`.",
+				Optional:            true,
+			},
+			"on_violation": schema.StringAttribute{
+				MarkdownDescription: "What to do when an assertion (`min`, `max`, `min4`, `max4`, `min6`, `max6`, `must_contain`, " +
+					"the prefix length limits and the change limits) is violated. `" + onViolationWarn + "` emits a warning and keeps the list unchanged and `" + onViolationError +
+					"` fails. `" + onViolationDrop + "` removes entries whose prefix length is outside of the limits from the list " +
+					"and fails for all other assertions. Defaults to `" + onViolationError + "`. " +
+					"The prefix length limits are checked against the fetched entries before `exclude`, `within`, `not_within` " +
+					"and `exclude_special` split them.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(onViolationDrop, onViolationWarn, onViolationError),
+				},
+			},
+			"expand": schema.BoolAttribute{
+				MarkdownDescription: "Populate `expanded_list` with every address in the entries of `list`. " +
+					"Useful for resources that only accept individual IPs.",
+				Optional: true,
+			},
+			"expand_max_prefix_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Throw an error if an entry contains more than this many addresses when `expand` is `true`. "+
+					"Defaults to `%d`.", defaultExpandMaxPrefixSize),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"expand_max_total": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Throw an error if `expanded_list` would contain more than this many addresses. "+
+					"Defaults to `%d`.", defaultExpandMaxTotal),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"baseline_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file storing the last accepted `list`. If set, `added` and `removed` are " +
+					"computed against it and the change limits are enforced. The file is created with the current `list` if it does not exist " +
+					"and only updated when `accept_changes` is `true`, so changes accumulate against the baseline until they are accepted. " +
+					"Note that the file is written whenever the data source is read with `accept_changes` set, including during plans.",
+				Optional: true,
+			},
+			"max_added": schema.Int64Attribute{
+				MarkdownDescription: "Throw an error if more than `max_added` entries were added since the baseline.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("baseline_file")),
+				},
+			},
+			"max_removed": schema.Int64Attribute{
+				MarkdownDescription: "Throw an error if more than `max_removed` entries were removed since the baseline.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("baseline_file")),
+				},
+			},
+			"max_change_percent": schema.Float64Attribute{
+				MarkdownDescription: "Throw an error if the number of added and removed entries is more than " +
+					"`max_change_percent` percent of the size of the baseline. Any change to an empty baseline is 100%.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+					float64validator.AlsoRequires(path.MatchRoot("baseline_file")),
+				},
+			},
+			"accept_changes": schema.BoolAttribute{
+				MarkdownDescription: "Accept the changes since the baseline, skipping the change limits, and update the baseline. " +
+					"Set it for a single run after reviewing `added` and `removed`.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("baseline_file")),
+				},
+			},
+			"chunk_size": schema.Int64Attribute{
+				MarkdownDescription: "Populate `chunks` with chunks of at most `chunk_size` entries. " +
+					"Useful for resources that limit the number of prefixes per rule.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"expanded_list": schema.ListAttribute{
+				MarkdownDescription: "List of every address in the entries of `list`, sorted by address, if `expand` is `true`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"overlaps": schema.ListNestedAttribute{
+				MarkdownDescription: "Every pair of entries where one entry contains the other, " +
+					"before `drop_redundant` is applied, if `find_overlaps` is `true`.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"covering": schema.StringAttribute{
+							MarkdownDescription: "The containing entry.",
+							Computed:            true,
+						},
+						"covered": schema.StringAttribute{
+							MarkdownDescription: "The contained entry.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"redundant": schema.ListAttribute{
+				MarkdownDescription: "Entries that are contained in another entry, before `drop_redundant` is applied. " +
+					"Of two entries covering the same prefix, only the second one is redundant. " +
+					"Only populated if `find_overlaps` is `true`.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"added": schema.ListAttribute{
+				MarkdownDescription: "Entries of `list` that are not in the baseline if `baseline_file` is set.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"removed": schema.ListAttribute{
+				MarkdownDescription: "Entries of the baseline that are not in `list` if `baseline_file` is set.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
 			"list": schema.ListAttribute{
-				MarkdownDescription: "List of IP addresses/prefixes.",
+				MarkdownDescription: "List of IP addresses/prefixes. Address ranges (`start-end`) are converted to the minimal list of prefixes covering them.",
 				Computed:            true,
 				ElementType:         types.StringType,
 			},
+			"list_ranges": schema.ListAttribute{
+				MarkdownDescription: "List of merged `start-end` address ranges covering `list`, sorted by address, " +
+					"if `address_ranges` is `true`.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"ptr_names": schema.MapAttribute{
+				MarkdownDescription: "Map of the single IPs of `list` to their reverse DNS names " +
+					"(`5.2.0.192.in-addr.arpa`, `...ip6.arpa`) if `reverse_dns` is `true`.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"reverse_zones": schema.ListAttribute{
+				MarkdownDescription: "List of the reverse DNS zones of the prefixes of `list`, sorted by address. " +
+					"Prefixes are split into zones at the next octet (IPv4) or nibble (IPv6) boundary. " +
+					"IPv4 prefixes longer than `/24` are named as in RFC 2317 (`64/26.2.0.192.in-addr.arpa`). " +
+					"Only populated if `reverse_dns` is `true`.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
 			"list_no_cidr": schema.ListAttribute{
 				MarkdownDescription: "List of IP addresses/prefixes with prefix length removed for single IPs if `no_cidr_single_ip` is `true`.",
 				Computed:            true,
 				ElementType:         types.StringType,
 			},
+			"list_netmask": schema.ListAttribute{
+				MarkdownDescription: "List of network addresses and netmasks separated by a space if `mask_notation` is `true`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"list_wildcard": schema.ListAttribute{
+				MarkdownDescription: "List of network addresses and wildcard masks separated by a space if `mask_notation` is `true`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"special": schema.MapAttribute{
+				MarkdownDescription: "Map of special-purpose address category to the entries overlapping it " +
+					"if `exclude_special` is set. Categories without entries are omitted.",
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "The entries of `list` with their parsed address details if `entry_details` is `true`.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: entryDetailsAttributes,
+				},
+			},
+			"entries_by_cidr": schema.MapNestedAttribute{
+				MarkdownDescription: "`entries` keyed by the network of the entry in CIDR notation (`192.0.2.5/24` is keyed by `192.0.2.0/24`). " +
+					"Of entries with the same network, such as `192.0.2.1` and `192.0.2.1/32`, only the first one in `list` is included. " +
+					"Only populated if `entry_details` is `true`. Useful for `for_each`.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: entryDetailsAttributes,
+				},
+			},
+			"chunks": schema.MapAttribute{
+				MarkdownDescription: "The entries of `list` split into chunks of at most `chunk_size` entries if `chunk_size` is set, " +
+					"keyed by the address range of the chunk in CIDR notation (e.g. `10.0.0.0/9`). " +
+					"Entries are assigned to chunks by address range so adding or removing an entry only changes " +
+					"the chunk it falls in, or splits or merges that chunk with its neighbours. The keys of other chunks " +
+					"do not change, so the map can be used with `for_each`. " +
+					"A chunk never contains both IPv4 and IPv6 entries.",
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+			},
 			"list4": schema.ListAttribute{
 				MarkdownDescription: "List of IPv4 addresses/prefixes if `split_af` is `true`.",
 				Computed:            true,
@@ -119,14 +703,243 @@ func (d *ListDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 				Computed:            true,
 				ElementType:         types.StringType,
 			},
+			"entry_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of entries in `list`. Equivalent to `length(list)`. " +
+					"Named `entry_count` because `count` is reserved by Terraform.",
+				Computed: true,
+			},
+			"count4": schema.Int64Attribute{
+				MarkdownDescription: "The number of IPv4 entries in `list`. Null if the list could not be parsed.",
+				Computed:            true,
+			},
+			"count6": schema.Int64Attribute{
+				MarkdownDescription: "The number of IPv6 entries in `list`. Null if the list could not be parsed.",
+				Computed:            true,
+			},
+			"total_addresses4": schema.Int64Attribute{
+				MarkdownDescription: "The number of unique IPv4 addresses covered by `list`. Null if the list could not be parsed.",
+				Computed:            true,
+			},
+			"total_addresses6": schema.StringAttribute{
+				MarkdownDescription: "The number of unique IPv6 addresses covered by `list` as a decimal string. " +
+					"Null if the list could not be parsed.",
+				Computed: true,
+			},
+			"largest_prefix": schema.StringAttribute{
+				MarkdownDescription: "The entry of `list` containing the most addresses. Null if the list is empty or could not be parsed.",
+				Computed:            true,
+			},
+			"smallest_prefix": schema.StringAttribute{
+				MarkdownDescription: "The entry of `list` containing the fewest addresses. Null if the list is empty or could not be parsed.",
+				Computed:            true,
+			},
+			"is_empty": schema.BoolAttribute{
+				MarkdownDescription: "Whether `list` is empty.",
+				Computed:            true,
+			},
+			"template": schema.StringAttribute{
+				MarkdownDescription: "Go [text/template](https://pkg.go.dev/text/template) used to populate `rendered`. " +
+					"The template is executed with `.List`, `.List4` and `.List6`. " +
+					"The functions `family`, `address`, `prefixlen`, `mask` and `wildcard` take an entry and return " +
+					"its address family (`4` or `6`), address, prefix length, netmask and wildcard mask. " +
+					"`join` takes a separator and a list.",
+				Optional: true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Built-in format used to populate `rendered`. One of `" + strings.Join(renderFormats(), "`, `") + "`. " +
+					"Firewall and access-list formats aggregate entries. Prefix-list formats keep every entry and number them from a hash of its prefix " +
+					"with a gap of 10 around each number, so adding or removing an entry does not renumber the others unless their hashes collide, " +
+					"which is more likely on `arista_eos` with its smaller sequence range. Entries are rendered in sequence order. " +
+					"Firewall and prefix-list formats render IPv4 and IPv6 entries into separate sets, chains, rules or lists except for `junos`, " +
+					"which renders a `route-filter-list` if `ge` or `le` apply to an entry.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(renderFormats()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("template")),
+				},
+			},
+			"format_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the set, chain, rule or list rendered by `format`. " +
+					"For formats with a set per address family, `4` or `6` is appended. " +
+					"Defaults to `" + defaultFormatName + "`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(formatNameRegexp, "must start with a letter and contain only letters, digits, `_` and `-`"),
+					stringvalidator.LengthAtMost(28),
+				},
+			},
+			"format_deny_all": schema.BoolAttribute{
+				MarkdownDescription: "Append a rule denying all other clients to the `nginx` format. " +
+					"`apache` and `envoy_rbac` deny unmatched clients without one.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"rpsl_origin": schema.StringAttribute{
+				MarkdownDescription: "The `origin` of `route` and `route6` objects rendered by the `" + formatRPSL + "` format, e.g. `AS64500`. " +
+					"Required for the `" + formatRPSL + "` format.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(rpslASNRegexp, "must be an AS number such as `AS64500`"),
+					stringvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"rpsl_mnt_by": schema.SetAttribute{
+				MarkdownDescription: "The `mnt-by` maintainers of objects rendered by the `" + formatRPSL + "` format. " +
+					"Required for the `" + formatRPSL + "` format.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(rpslObjectRegexp, "must be a valid RPSL object name")),
+					setvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"rpsl_source": schema.StringAttribute{
+				MarkdownDescription: "The `source` registry of objects rendered by the `" + formatRPSL + "` format, e.g. `RIPE`. " +
+					"Required for the `" + formatRPSL + "` format.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(rpslSourceRegexp, "must be an upper case registry name such as `RIPE`"),
+					stringvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"rpsl_route_set": schema.StringAttribute{
+				MarkdownDescription: "Name of a `route-set` rendered by the `" + formatRPSL + "` format with the entries as " +
+					"`members` (IPv4) and `mp-members` (IPv6), e.g. `AS64500:RS-EXAMPLE`. The route-set is omitted if not set.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(rpslRouteSetRegexp, "must be a route-set name such as `RS-EXAMPLE` or `AS64500:RS-EXAMPLE`"),
+					stringvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"prefix_list_ge4": schema.Int64Attribute{
+				MarkdownDescription: "The `ge` of IPv4 prefix-list entries rendered by `format`. " +
+					"Omitted for entries with a prefix length of at least this value.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 32),
+					int64validator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"prefix_list_le4": schema.Int64Attribute{
+				MarkdownDescription: "The `le` of IPv4 prefix-list entries rendered by `format`. " +
+					"Omitted for entries with a prefix length of at least this value.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 32),
+					int64validator.AtLeastSumOf(path.MatchRoot("prefix_list_ge4")),
+					int64validator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"prefix_list_ge6": schema.Int64Attribute{
+				MarkdownDescription: "The `ge` of IPv6 prefix-list entries rendered by `format`. " +
+					"Omitted for entries with a prefix length of at least this value.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 128),
+					int64validator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"prefix_list_le6": schema.Int64Attribute{
+				MarkdownDescription: "The `le` of IPv6 prefix-list entries rendered by `format`. " +
+					"Omitted for entries with a prefix length of at least this value.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 128),
+					int64validator.AtLeastSumOf(path.MatchRoot("prefix_list_ge6")),
+					int64validator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+			"rendered": schema.StringAttribute{
+				MarkdownDescription: "The list rendered with `template` or `format`.",
+				Computed:            true,
+			},
+			"ip_block_prefixes": schema.SetAttribute{
+				MarkdownDescription: "IP addresses/prefixes combined with the entries of `list` according to `ip_block_mode` " +
+					"to populate `ip_blocks` and `cilium_cidr_set`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"ip_block_mode": schema.StringAttribute{
+				MarkdownDescription: "`" + ipBlockModeExceptList + "` allows `ip_block_prefixes` except the entries of `list`. " +
+					"`" + ipBlockModeExceptPrefixes + "` allows the entries of `list` except `ip_block_prefixes`. " +
+					"Defaults to `" + ipBlockModeExceptList + "`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ipBlockModeExceptList, ipBlockModeExceptPrefixes),
+					stringvalidator.AlsoRequires(path.MatchRoot("ip_block_prefixes")),
+				},
+			},
+			"ip_blocks": schema.StringAttribute{
+				MarkdownDescription: "JSON list of Kubernetes NetworkPolicy peers (`{\"ipBlock\": {\"cidr\": ..., \"except\": [...]}}`) " +
+					"if `ip_block_prefixes` is set. Allowed and excluded prefixes are aggregated and allowed prefixes " +
+					"covered by an excluded prefix are omitted.",
+				Computed: true,
+			},
+			"cilium_cidr_set": schema.StringAttribute{
+				MarkdownDescription: "JSON list of Cilium CIDR rules (`{\"cidr\": ..., \"except\": [...]}`) for `toCIDRSet` and `fromCIDRSet` " +
+					"if `ip_block_prefixes` is set. Contains the same blocks as `ip_blocks`.",
+				Computed: true,
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "Hex encoded SHA-256 fingerprint of `list`. Only changes when the contents of `list` change.",
+				Computed:            true,
+			},
+			"revision": schema.StringAttribute{
+				MarkdownDescription: "The first " + strconv.Itoa(revisionLength) + " characters of `sha256`. " +
+					"Useful as a short trigger, resource name or tag.",
+				Computed: true,
+			},
 			// https://developer.hashicorp.com/terraform/plugin/framework/acctests#implement-id-attribute
 			// https://github.com/hashicorp/terraform-plugin-sdk/issues/1072
 			// https://discuss.hashicorp.com/t/provider-plugin-framework-data-source-with-no-id/33571
-			"id": schema.StringAttribute{Computed: true},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Deterministic ID derived from `endpoint`, the filter and `list`.",
+				Computed:            true,
+			},
 		},
 	}
 }
 
+func (d *ListDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data ListDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Template.IsNull() && !data.Template.IsUnknown() {
+		if _, err := parseTemplate(data.Template.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("template"),
+				"Invalid template",
+				fmt.Sprintf("Error parsing template: %v", err),
+			)
+		}
+	}
+
+	if data.Format.ValueString() == formatRPSL {
+		for _, a := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"rpsl_origin", data.RPSLOrigin},
+			{"rpsl_mnt_by", data.RPSLMntBy},
+			{"rpsl_source", data.RPSLSource},
+		} {
+			if a.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(a.name),
+					"Missing required attribute",
+					fmt.Sprintf("%s is required when format is %q.", a.name, formatRPSL),
+				)
+			}
+		}
+	}
+}
+
 func (d *ListDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -178,105 +991,538 @@ func (d *ListDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	}
 	tflog.Debug(ctx, "received list", map[string]interface{}{"count": len(list)})
 
-	sort.Strings(list)
+	var diag diag.Diagnostics
+	entries, err := parseEntries(list)
+	parsed := err == nil
+	if !parsed {
+		if data.needsParsing() {
+			var pe *parseEntryError
+			if errors.As(err, &pe) {
+				resp.Diagnostics.AddError(pe.summary, pe.detail)
+			} else {
+				resp.Diagnostics.AddError("Error parsing list", err.Error())
+			}
+			return
+		}
+		// Return the list as is. Outputs derived from the parsed entries are left null.
+		tflog.Warn(ctx, "unable to parse list", map[string]interface{}{"error": err.Error()})
+		sort.Strings(list)
+	} else {
+		entries = d.processEntries(ctx, &data, entries, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		list = entryStrings(entries)
+	}
 
 	if !data.Min.IsNull() && len(list) < int(data.Min.ValueInt64()) {
-		resp.Diagnostics.AddError(
+		data.addViolation(
+			&resp.Diagnostics,
 			"List length is less than min",
 			fmt.Sprintf("The list has length (%d) less than the min (%d)", len(list), data.Min.ValueInt64()),
 		)
 	}
 	if !data.Max.IsNull() && len(list) > int(data.Max.ValueInt64()) {
-		resp.Diagnostics.AddError(
+		data.addViolation(
+			&resp.Diagnostics,
 			"List length is greater than min",
 			fmt.Sprintf("The list has length (%d) greater than the max (%d)", len(list), data.Max.ValueInt64()),
 		)
 	}
+	count4, count6 := countFamilies(entries)
+	if !data.Min4.IsNull() && count4 < int(data.Min4.ValueInt64()) {
+		data.addViolation(
+			&resp.Diagnostics,
+			"IPv4 count is less than min4",
+			fmt.Sprintf("The list has %d IPv4 entries, less than the min4 (%d)", count4, data.Min4.ValueInt64()),
+		)
+	}
+	if !data.Max4.IsNull() && count4 > int(data.Max4.ValueInt64()) {
+		data.addViolation(
+			&resp.Diagnostics,
+			"IPv4 count is greater than max4",
+			fmt.Sprintf("The list has %d IPv4 entries, greater than the max4 (%d)", count4, data.Max4.ValueInt64()),
+		)
+	}
+	if !data.Min6.IsNull() && count6 < int(data.Min6.ValueInt64()) {
+		data.addViolation(
+			&resp.Diagnostics,
+			"IPv6 count is less than min6",
+			fmt.Sprintf("The list has %d IPv6 entries, less than the min6 (%d)", count6, data.Min6.ValueInt64()),
+		)
+	}
+	if !data.Max6.IsNull() && count6 > int(data.Max6.ValueInt64()) {
+		data.addViolation(
+			&resp.Diagnostics,
+			"IPv6 count is greater than max6",
+			fmt.Sprintf("The list has %d IPv6 entries, greater than the max6 (%d)", count6, data.Max6.ValueInt64()),
+		)
+	}
+
+	if !data.MustContain.IsNull() {
+		required := parsePrefixSet(ctx, data.MustContain, path.Root("must_contain"), &resp.Diagnostics)
+		if missing := missingPrefixes(entries, required); len(missing) > 0 {
+			data.addViolation(
+				&resp.Diagnostics,
+				"Required entries missing",
+				fmt.Sprintf(
+					"The list does not cover the following entries of must_contain: %s",
+					strings.Join(prefixStrings(missing), ", "),
+				),
+			)
+		}
+	}
+
+	if !data.BaselineFile.IsNull() && !resp.Diagnostics.HasError() {
+		checkBaseline(ctx, &data, list, &resp.Diagnostics)
+	}
 
-	var diag diag.Diagnostics
 	data.List, diag = types.ListValueFrom(ctx, types.StringType, list)
 	resp.Diagnostics.Append(diag...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if data.SplitAF.ValueBool() || data.NoCIDRSingleIP.ValueBool() {
+	data.EntryCount = types.Int64Value(int64(len(list)))
+	data.IsEmpty = types.BoolValue(len(list) == 0)
+
+	if parsed {
 		list4 := []string{}
 		list6 := []string{}
 		var listNoCIDR []string
 		if data.NoCIDRSingleIP.ValueBool() {
-			listNoCIDR = make([]string, 0, len(list))
-		}
-		for _, e := range list {
-			if strings.Contains(e, "/") {
-				p, err := netip.ParsePrefix(e)
-				if err != nil {
-					resp.Diagnostics.AddError(
-						"Error parsing IP/prefix",
-						fmt.Sprintf("Error parsing %q: %v", e, err),
-					)
-					return
-				}
-				if data.SplitAF.ValueBool() {
-					if p.Addr().Is4() {
-						list4 = append(list4, e)
-					} else if p.Addr().Is6() {
-						list6 = append(list6, e)
-					} else {
-						resp.Diagnostics.AddError(
-							"IP/prefix is not IPv4 or IPv6",
-							fmt.Sprintf("%q is not IPv4 or IPv6", e),
-						)
-						return
-					}
-				}
-				if data.NoCIDRSingleIP.ValueBool() {
-					if p.IsSingleIP() {
-						listNoCIDR = append(listNoCIDR, p.Addr().String())
-					} else {
-						listNoCIDR = append(listNoCIDR, e)
-					}
+			listNoCIDR = make([]string, 0, len(entries))
+		}
+		var stats listStats
+		for _, e := range entries {
+			stats.add(e)
+			if data.SplitAF.ValueBool() {
+				if e.prefix.Addr().Is4() {
+					list4 = append(list4, e.String())
+				} else {
+					list6 = append(list6, e.String())
 				}
+			}
+			if data.NoCIDRSingleIP.ValueBool() {
+				listNoCIDR = append(listNoCIDR, e.NoCIDRString())
+			}
+		}
+		// no_cidr_single_ip alone sets list4 and list6 to empty lists.
+		if data.SplitAF.ValueBool() || data.NoCIDRSingleIP.ValueBool() {
+			data.List4, diag = types.ListValueFrom(ctx, types.StringType, list4)
+			resp.Diagnostics.Append(diag...)
+			data.List6, diag = types.ListValueFrom(ctx, types.StringType, list6)
+			resp.Diagnostics.Append(diag...)
+		}
+		data.ListNoCIDR, diag = types.ListValueFrom(ctx, types.StringType, listNoCIDR)
+		resp.Diagnostics.Append(diag...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.Count4 = types.Int64Value(int64(stats.count4))
+		data.Count6 = types.Int64Value(int64(stats.count6))
+		data.TotalAddresses4 = types.Int64Value(stats.totalAddresses4().Int64())
+		data.TotalAddresses6 = types.StringValue(stats.totalAddresses6().String())
+		if !stats.empty() {
+			data.LargestPrefix = types.StringValue(stats.largest.String())
+			data.SmallestPrefix = types.StringValue(stats.smallest.String())
+		}
+	}
+
+	if data.MaskNotation.ValueBool() {
+		listNetmask := make([]string, 0, len(entries))
+		listWildcard := make([]string, 0, len(entries))
+		var ipv6 []string
+		for _, e := range entries {
+			if e.prefix.Addr().Is4() {
+				listNetmask = append(listNetmask, e.NetmaskString())
+				listWildcard = append(listWildcard, e.WildcardString())
+				continue
+			}
+			switch data.maskIPv6() {
+			case maskIPv6CIDR:
+				listNetmask = append(listNetmask, e.String())
+				listWildcard = append(listWildcard, e.String())
+			case maskIPv6Error:
+				ipv6 = append(ipv6, e.String())
+			}
+		}
+		if len(ipv6) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("mask_notation_ipv6"),
+				"IPv6 entries in mask notation",
+				fmt.Sprintf("IPv6 entries cannot be expressed in mask notation: %s", strings.Join(ipv6, ", ")),
+			)
+			return
+		}
+		data.ListNetmask, diag = types.ListValueFrom(ctx, types.StringType, listNetmask)
+		resp.Diagnostics.Append(diag...)
+		data.ListWildcard, diag = types.ListValueFrom(ctx, types.StringType, listWildcard)
+		resp.Diagnostics.Append(diag...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.Expand.ValueBool() {
+		maxSize := int64(defaultExpandMaxPrefixSize)
+		if !data.ExpandMaxSize.IsNull() {
+			maxSize = data.ExpandMaxSize.ValueInt64()
+		}
+		maxTotal := int64(defaultExpandMaxTotal)
+		if !data.ExpandMaxTotal.IsNull() {
+			maxTotal = data.ExpandMaxTotal.ValueInt64()
+		}
+		expanded, err := expandEntries(entries, maxSize, maxTotal)
+		if err != nil {
+			var le *expandLimitError
+			if errors.As(err, &le) {
+				resp.Diagnostics.AddError(le.summary, le.detail)
 			} else {
-				p, err := netip.ParseAddr(e)
-				if err != nil {
-					resp.Diagnostics.AddError(
-						"Error parsing IP",
-						fmt.Sprintf("Error parsing %q: %v", e, err),
-					)
-					return
-				}
-				if data.SplitAF.ValueBool() {
-					if p.Is4() {
-						list4 = append(list4, e)
-					} else if p.Is6() {
-						list6 = append(list6, e)
-					} else {
-						resp.Diagnostics.AddError(
-							"IP is not IPv4 or IPv6",
-							fmt.Sprintf("%q is not IPv4 or IPv6", e),
-						)
-						return
-					}
-				}
-				if data.NoCIDRSingleIP.ValueBool() {
-					listNoCIDR = append(listNoCIDR, e)
-				}
+				resp.Diagnostics.AddError("Error expanding list", err.Error())
 			}
+			return
 		}
-		data.List4, diag = types.ListValueFrom(ctx, types.StringType, list4)
+		data.ExpandedList, diag = types.ListValueFrom(ctx, types.StringType, expanded)
 		resp.Diagnostics.Append(diag...)
-		data.List6, diag = types.ListValueFrom(ctx, types.StringType, list6)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.AddressRanges.ValueBool() {
+		data.ListRanges, diag = types.ListValueFrom(ctx, types.StringType, entryRanges(entries))
 		resp.Diagnostics.Append(diag...)
-		data.ListNoCIDR, diag = types.ListValueFrom(ctx, types.StringType, listNoCIDR)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.ReverseDNS.ValueBool() {
+		ptrNames, reverseZones := reverseDNS(entries)
+		data.PTRNames, diag = types.MapValueFrom(ctx, types.StringType, ptrNames)
+		resp.Diagnostics.Append(diag...)
+		data.ReverseZones, diag = types.ListValueFrom(ctx, types.StringType, reverseZones)
 		resp.Diagnostics.Append(diag...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
 
-	data.ID = types.StringValue(id.UniqueId())
+	if data.EntryDetails.ValueBool() {
+		details := make([]entryDetails, 0, len(entries))
+		for _, e := range entries {
+			details = append(details, e.Details())
+		}
+		data.Entries, diag = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: entryDetailsAttrTypes}, details)
+		resp.Diagnostics.Append(diag...)
+		data.EntriesByCIDR, diag = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: entryDetailsAttrTypes}, entriesByCIDR(entries))
+		resp.Diagnostics.Append(diag...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !data.IPBlockPrefixes.IsNull() {
+		prefixes := parsePrefixSet(ctx, data.IPBlockPrefixes, path.Root("ip_block_prefixes"), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var blocks []ipBlock
+		if data.ipBlockMode() == ipBlockModeExceptPrefixes {
+			blocks = computeIPBlocks(aggregateEntries(entries), prefixes)
+		} else {
+			blocks = computeIPBlocks(prefixes, aggregateEntries(entries))
+		}
+
+		ipBlocks, err := kubernetesIPBlocksJSON(blocks)
+		if err != nil {
+			resp.Diagnostics.AddError("Error encoding ip_blocks", err.Error())
+			return
+		}
+		cidrSet, err := ciliumCIDRSetJSON(blocks)
+		if err != nil {
+			resp.Diagnostics.AddError("Error encoding cilium_cidr_set", err.Error())
+			return
+		}
+		data.IPBlocks = types.StringValue(ipBlocks)
+		data.CiliumCIDRSet = types.StringValue(cidrSet)
+	}
+
+	if !data.Template.IsNull() {
+		rendered, err := renderTemplate(data.Template.ValueString(), entries)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("template"),
+				"Error rendering template",
+				fmt.Sprintf("Error rendering template: %v", err),
+			)
+			return
+		}
+		data.Rendered = types.StringValue(rendered)
+	}
+
+	if !data.Format.IsNull() {
+		opts := renderOptions{
+			name: defaultFormatName,
+			ge4:  int(data.PrefixListGe4.ValueInt64()),
+			le4:  int(data.PrefixListLe4.ValueInt64()),
+			ge6:  int(data.PrefixListGe6.ValueInt64()),
+			le6:  int(data.PrefixListLe6.ValueInt64()),
+
+			denyAll: data.FormatDenyAll.ValueBool(),
+			rpsl: rpslOptions{
+				origin:   data.RPSLOrigin.ValueString(),
+				source:   data.RPSLSource.ValueString(),
+				routeSet: data.RPSLRouteSet.ValueString(),
+			},
+		}
+		if !data.FormatName.IsNull() {
+			opts.name = data.FormatName.ValueString()
+		}
+		if !data.RPSLMntBy.IsNull() {
+			resp.Diagnostics.Append(data.RPSLMntBy.ElementsAs(ctx, &opts.rpsl.mntBy, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			sort.Strings(opts.rpsl.mntBy)
+		}
+		rendered, err := renderers[data.Format.ValueString()](entries, opts)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("format"),
+				"Error rendering list",
+				fmt.Sprintf("Error rendering the list as %s: %v", data.Format.ValueString(), err),
+			)
+			return
+		}
+		data.Rendered = types.StringValue(rendered)
+	}
+
+	if !data.ChunkSize.IsNull() {
+		data.Chunks, diag = types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, chunkEntries(entries, int(data.ChunkSize.ValueInt64())))
+		resp.Diagnostics.Append(diag...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	data.SHA256 = types.StringValue(listFingerprint(list))
+	data.Revision = types.StringValue(data.SHA256.ValueString()[:revisionLength])
+	data.ID = types.StringValue(listID(data.Endpoint.ValueString(), filter, list))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// checkBaseline compares list to the baseline file configured in m, sets
+// the added and removed outputs and enforces the change limits.
+// The baseline is updated if the changes are accepted.
+func checkBaseline(ctx context.Context, m *ListDataSourceModel, list []string, diags *diag.Diagnostics) {
+	file := m.BaselineFile.ValueString()
+	base, exists, err := readBaseline(file)
+	if err != nil {
+		diags.AddAttributeError(path.Root("baseline_file"), "Error reading baseline", err.Error())
+		return
+	}
+
+	added, removed := diffLists(base, list)
+	var diag diag.Diagnostics
+	m.Added, diag = types.ListValueFrom(ctx, types.StringType, added)
+	diags.Append(diag...)
+	m.Removed, diag = types.ListValueFrom(ctx, types.StringType, removed)
+	diags.Append(diag...)
+
+	// The first read creates the baseline.
+	violated := false
+	if exists && !m.AcceptChanges.ValueBool() {
+		if !m.MaxAdded.IsNull() && len(added) > int(m.MaxAdded.ValueInt64()) {
+			violated = true
+			m.addViolation(
+				diags,
+				"Too many entries added",
+				fmt.Sprintf(
+					"%d entries were added since the baseline, more than the max_added (%d). "+
+						"Set accept_changes to true to accept the changes.",
+					len(added), m.MaxAdded.ValueInt64(),
+				),
+			)
+		}
+		if !m.MaxRemoved.IsNull() && len(removed) > int(m.MaxRemoved.ValueInt64()) {
+			violated = true
+			m.addViolation(
+				diags,
+				"Too many entries removed",
+				fmt.Sprintf(
+					"%d entries were removed since the baseline, more than the max_removed (%d). "+
+						"Set accept_changes to true to accept the changes.",
+					len(removed), m.MaxRemoved.ValueInt64(),
+				),
+			)
+		}
+		if pct := changePercent(len(base), len(added)+len(removed)); !m.MaxChangePct.IsNull() && pct > m.MaxChangePct.ValueFloat64() {
+			violated = true
+			m.addViolation(
+				diags,
+				"Too many entries changed",
+				fmt.Sprintf(
+					"%d entries were added and %d removed since the baseline (%.1f%%), more than the max_change_percent (%g%%). "+
+						"Set accept_changes to true to accept the changes.",
+					len(added), len(removed), pct, m.MaxChangePct.ValueFloat64(),
+				),
+			)
+		}
+	}
+	if violated || diags.HasError() {
+		return
+	}
+
+	// The baseline only moves when the changes are accepted explicitly, so
+	// changes spread over several reads are still measured against it.
+	// With accept_changes set, any read that sees changes writes the file,
+	// including reads during plans.
+	if !exists || (m.AcceptChanges.ValueBool() && (len(added) > 0 || len(removed) > 0)) {
+		if err := writeBaseline(file, list); err != nil {
+			diags.AddAttributeError(path.Root("baseline_file"), "Error writing baseline", err.Error())
+			return
+		}
+		tflog.Info(ctx, "updated baseline", map[string]interface{}{"file": file, "added": len(added), "removed": len(removed)})
+	}
+}
+
+// processEntries applies the filters and transformations configured in m to entries.
+// The returned entries are sorted and unique.
+func (d *ListDataSource) processEntries(ctx context.Context, m *ListDataSourceModel, entries []listEntry, diags *diag.Diagnostics) []listEntry {
+	var diag diag.Diagnostics
+
+	if !m.Normalize.IsNull() {
+		var modes []string
+		diags.Append(m.Normalize.ElementsAs(ctx, &modes, false)...)
+		if diags.HasError() {
+			return nil
+		}
+		normalize := map[string]bool{}
+		for _, mode := range modes {
+			normalize[mode] = true
+		}
+		if normalize[normalizeMaskHostBits] && normalize[normalizeHostRoute] {
+			diags.AddAttributeError(
+				path.Root("normalize"),
+				"Invalid normalize modes",
+				fmt.Sprintf("%q and %q are mutually exclusive.", normalizeMaskHostBits, normalizeHostRoute),
+			)
+			return nil
+		}
+		entries = normalizeEntries(entries, normalize)
+	}
+
+	// The limits apply to the fetched entries, before they are split by
+	// exclusions, so violations name the entries as they are in NetBox.
+	// Violating entries are only removed when on_violation is drop.
+	inRange, violations := filterPrefixLength(entries, m.prefixLengthLimits())
+	if len(violations) > 0 {
+		sortEntries(violations)
+		summary := "Prefix length out of range"
+		detail := fmt.Sprintf(
+			"The following entries have a prefix length outside of the configured limits: %s",
+			strings.Join(entryStrings(violations), ", "),
+		)
+		if m.OnViolation.ValueString() == onViolationDrop {
+			tflog.Info(ctx, "dropped entries with prefix length out of range", map[string]interface{}{"entries": entryStrings(violations)})
+			entries = inRange
+		} else {
+			m.addViolation(diags, summary, detail)
+			if diags.HasError() {
+				return nil
+			}
+		}
+	}
+
+	if !m.Exclude.IsNull() {
+		exclude := parsePrefixSet(ctx, m.Exclude, path.Root("exclude"), diags)
+		if diags.HasError() {
+			return nil
+		}
+		entries = excludeEntries(entries, exclude)
+	}
+
+	if !m.Within.IsNull() {
+		within := parsePrefixSet(ctx, m.Within, path.Root("within"), diags)
+		if diags.HasError() {
+			return nil
+		}
+		entries = filterWithin(entries, within, m.partialOverlap())
+	}
+	if !m.NotWithin.IsNull() {
+		notWithin := parsePrefixSet(ctx, m.NotWithin, path.Root("not_within"), diags)
+		if diags.HasError() {
+			return nil
+		}
+		entries = filterNotWithin(entries, notWithin, m.partialOverlap())
+	}
+
+	if !m.ExcludeSpecial.IsNull() {
+		var categories []string
+		diags.Append(m.ExcludeSpecial.ElementsAs(ctx, &categories, false)...)
+		m.Special, diag = types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, classifySpecial(entries))
+		diags.Append(diag...)
+		if diags.HasError() {
+			return nil
+		}
+		entries = excludeEntries(entries, specialPrefixes(categories))
+	}
+
+	sortEntries(entries)
+	entries = uniqueEntries(entries)
+
+	if !m.FindOverlaps.ValueBool() && !m.DropRedundant.ValueBool() {
+		return entries
+	}
+
+	overlaps, redundant := findOverlaps(entries)
+	kept := make([]listEntry, 0, len(entries))
+	redundantList := []string{}
+	for i, e := range entries {
+		if redundant[i] {
+			redundantList = append(redundantList, e.String())
+			if m.DropRedundant.ValueBool() {
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+	if m.FindOverlaps.ValueBool() {
+		m.Overlaps, diag = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: entryOverlapAttrTypes}, overlaps)
+		diags.Append(diag...)
+		m.Redundant, diag = types.ListValueFrom(ctx, types.StringType, redundantList)
+		diags.Append(diag...)
+	}
+	return kept
+}
+
+// parsePrefixSet parses each element of set as an IP address or prefix.
+// Errors are added to diags against attr.
+func parsePrefixSet(ctx context.Context, set types.Set, attr path.Path, diags *diag.Diagnostics) []netip.Prefix {
+	var elems []string
+	diags.Append(set.ElementsAs(ctx, &elems, false)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	ret := make([]netip.Prefix, 0, len(elems))
+	for _, e := range elems {
+		p, err := parsePrefix(e)
+		if err != nil {
+			diags.AddAttributeError(
+				attr,
+				"Error parsing IP/prefix",
+				fmt.Sprintf("Error parsing %q: %v", e, err),
+			)
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
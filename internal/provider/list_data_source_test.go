@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -38,6 +41,37 @@ func TestListDataSource(t *testing.T) {
 			map[string][]string{"tag": {"p1"}, "summarize": {"false"}},
 			[]string{"192.0.2.0/27", "192.0.2.200/32", "2001:db8::/64", "2001:db8::200/128"},
 		)
+		h.addList(
+			"prefixes",
+			map[string][]string{"tag": {"corp-egress"}},
+			[]string{"10.20.0.0/22", "192.0.2.9/32", "198.51.100.0/24"},
+		)
+		h.addList(
+			"aggregates",
+			map[string][]string{"tag": {"broad"}},
+			[]string{"0.0.0.0/0", "10.0.0.0/8", "192.0.2.0/24", "::/0", "2001:db8::/48"},
+		)
+		h.addList(
+			"ip-addresses",
+			map[string][]string{"tag": {"public"}},
+			[]string{"10.1.2.3/32", "100.64.0.1/32", "172.0.0.0/8", "203.0.113.7/32", "fe80::1/128", "2606:4700::1/128"},
+		)
+		h.addList(
+			"ip-addresses",
+			map[string][]string{"tag": {"interfaces"}},
+			[]string{"192.0.2.5/24", "192.0.2.9/24", "::ffff:198.51.100.1", "2001:DB8:0:0::1/64"},
+		)
+		h.addList(
+			"prefixes",
+			map[string][]string{"tag": {"appliances"}},
+			[]string{"192.0.2.8/29", "192.0.2.12/30", "198.51.100.1/32"},
+		)
+		h.addList(
+			"ip-ranges",
+			map[string][]string{"tag": {"dhcp"}},
+			[]string{"192.0.2.10-192.0.2.20", "192.0.2.21/32", "2001:db8::-2001:db8::ff"},
+		)
+		h.addList("ip-ranges", map[string][]string{"tag": {"invalid"}}, []string{"192.0.2.20-192.0.2.10"})
 		s := httptest.NewServer(h)
 		defer s.Close()
 		url = s.URL
@@ -145,6 +179,14 @@ data "nblists_list" "ten" {
 	no_cidr_single_ip = true
 }
 
+// no CIDR without split af
+data "nblists_list" "no_cidr_only" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["p1"] }
+	summarize = false
+	no_cidr_single_ip = true
+}
+
 // split af on single IPs (no CIDR)
 data "nblists_list" "eleven" {
 	endpoint = "ip-addresses"
@@ -154,6 +196,244 @@ data "nblists_list" "eleven" {
 	split_af = true
 	no_cidr_single_ip = true
 }
+
+// exclude
+data "nblists_list" "exclude" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	exclude = ["10.20.2.64/26", "192.0.2.9"]
+	split_af = true
+}
+
+// prefix length limits
+data "nblists_list" "prefix_length" {
+	endpoint = "aggregates"
+	filter = { "tag" = ["broad"] }
+	min_prefix_length4 = 16
+	min_prefix_length6 = 32
+	max_prefix_length6 = 64
+	on_violation = "drop"
+}
+
+// prefix length limits with warnings
+data "nblists_list" "prefix_length_warn" {
+	endpoint = "aggregates"
+	filter = { "tag" = ["broad"] }
+	min_prefix_length4 = 16
+	min_prefix_length6 = 32
+	max_prefix_length6 = 64
+	on_violation = "warn"
+}
+
+// prefix length limits are checked before exclude
+data "nblists_list" "prefix_length_exclude" {
+	endpoint = "aggregates"
+	filter = { "tag" = ["broad"] }
+	exclude = ["10.0.0.0/8"]
+	min_prefix_length4 = 8
+	min_prefix_length6 = 32
+	on_violation = "drop"
+}
+
+// within and not_within
+data "nblists_list" "within" {
+	endpoint = "aggregates"
+	filter = { "tag" = ["broad"] }
+	within = ["10.20.0.0/16", "192.0.2.0/24"]
+	not_within = ["10.20.128.0/17"]
+	partial_overlap = "clip"
+}
+
+// exclude_special
+data "nblists_list" "special" {
+	endpoint = "ip-addresses"
+	filter = { "tag" = ["public"] }
+	exclude_special = ["private", "shared", "link_local"]
+}
+
+// chunk_size
+data "nblists_list" "chunks" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["p1"] }
+	summarize = false
+	chunk_size = 2
+}
+
+// normalize
+data "nblists_list" "normalize" {
+	endpoint = "ip-addresses"
+	filter = { "tag" = ["interfaces"] }
+	normalize = ["mask_host_bits", "unmap_ipv4", "canonical"]
+}
+
+// normalize to host routes
+data "nblists_list" "normalize_host_route" {
+	endpoint = "ip-addresses"
+	filter = { "tag" = ["interfaces"] }
+	normalize = ["host_route"]
+}
+
+// expand
+data "nblists_list" "expand" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["appliances"] }
+	expand = true
+	expand_max_prefix_size = 8
+	expand_max_total = 9
+}
+
+// min violated with on_violation = "warn"
+data "nblists_list" "min_warn" {
+	endpoint = "ip-addresses"
+	min = 2
+	max4 = 0
+	filter = { "tag" = ["6"] }
+	on_violation = "warn"
+}
+
+// overlaps and drop_redundant
+data "nblists_list" "redundant" {
+	endpoint = "aggregates"
+	filter = { "tag" = ["broad"] }
+	drop_redundant = true
+	find_overlaps = true
+}
+
+// statistics
+data "nblists_list" "stats" {
+	endpoint = "aggregates"
+	filter = { "tag" = ["broad"] }
+}
+
+// template
+data "nblists_list" "template" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	template = "{{range .List4}}allow {{.}};\n{{end}}"
+}
+
+// template functions
+data "nblists_list" "template_funcs" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	template = "{{range .List}}{{address .}} {{mask .}} {{wildcard .}} /{{prefixlen .}} v{{family .}}|{{end}}{{join \",\" .List6}}"
+}
+
+// entries_by_cidr with host bits set
+data "nblists_list" "entries_by_cidr_masked" {
+	endpoint = "ip-addresses"
+	filter = { "tag" = ["interfaces"] }
+	entry_details = true
+}
+
+// reverse dns
+data "nblists_list" "reverse_dns" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	reverse_dns = true
+}
+
+// format
+data "nblists_list" "format" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	format = "ipset"
+	format_name = "egress"
+}
+
+// prefix-list format
+data "nblists_list" "format_prefix_list" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	format = "cisco_ios"
+	prefix_list_le4 = 24
+}
+
+// access-list format
+data "nblists_list" "format_deny_all" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	format = "nginx"
+	format_deny_all = true
+}
+
+// rpsl format
+data "nblists_list" "format_rpsl" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	format = "rpsl"
+	rpsl_origin = "AS64500"
+	rpsl_mnt_by = ["MAINT-B", "MAINT-A"]
+	rpsl_source = "RIPE"
+	rpsl_route_set = "RS-EGRESS"
+}
+
+// ip blocks
+data "nblists_list" "ip_blocks" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	ip_block_prefixes = ["10.0.0.0/8", "192.0.2.9/32"]
+}
+
+// ip blocks with the list allowed
+data "nblists_list" "ip_blocks_except_prefixes" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	ip_block_prefixes = ["10.20.1.0/24", "198.51.100.128/25"]
+	ip_block_mode = "except_prefixes"
+}
+
+// must_contain
+data "nblists_list" "must_contain" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["corp-egress"] }
+	must_contain = ["10.20.1.5", "198.51.100.0/25", "192.0.2.9/32"]
+}
+
+// per-family counts
+data "nblists_list" "family_counts" {
+	endpoint = "ip-addresses"
+	filter = { "tag" = ["11"] }
+	summarize = false
+	as_cidr = false
+	min4 = 1
+	max4 = 1
+	min6 = 1
+	max6 = 1
+}
+
+// ranges
+data "nblists_list" "ranges" {
+	endpoint = "ip-ranges"
+	filter = { "tag" = ["dhcp"] }
+	split_af = true
+	address_ranges = true
+}
+
+// mask_notation
+data "nblists_list" "netmask" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["p1"] }
+	summarize = false
+	mask_notation = true
+}
+
+// mask_notation with IPv6 in CIDR notation
+data "nblists_list" "netmask_cidr" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["p1"] }
+	summarize = false
+	mask_notation = true
+	mask_notation_ipv6 = "cidr"
+}
+
+// entries
+data "nblists_list" "entries" {
+	endpoint = "prefixes"
+	filter = { "tag" = ["p1"] }
+	summarize = false
+	entry_details = true
+}
 `,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(
@@ -170,6 +450,44 @@ data "nblists_list" "eleven" {
 						"data.nblists_list.one",
 						"list_no_cidr",
 					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"list_ranges",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"ptr_names",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"reverse_zones",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"overlaps",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"redundant",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"entries",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.one",
+						"entries_by_cidr",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.one",
+						"sha256",
+						"2a9e60320771724c2e26f04bf3b72c7028987d06a2b5ca38bc902fe109b01c14",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.one",
+						"revision",
+						"2a9e60320771",
+					),
 
 					resource.TestCheckResourceAttr(
 						"data.nblists_list.two",
@@ -423,98 +741,1260 @@ data "nblists_list" "eleven" {
 						"list6.#",
 						"1",
 					),
-				),
-			},
-		},
-	})
 
-	// with allow_empty_filter=true
-	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: fmt.Sprintf(`
-provider "nblists" {
-    url = "%s"
-	token = "%s"
-	allow_empty_filter = true
-	request_timeout = 20
-}
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list.#",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list.0",
+						"10.20.0.0/23",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list.1",
+						"10.20.2.0/26",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list.2",
+						"10.20.2.128/25",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list.3",
+						"10.20.3.0/24",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list.4",
+						"198.51.100.0/24",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list4.#",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.exclude",
+						"list6.#",
+						"0",
+					),
 
-data "nblists_list" "test" {
-	endpoint = "aggregates"
-}
-`, url, token),
-				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"data.nblists_list.test",
+						"data.nblists_list.prefix_length",
+						"list.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length",
 						"list.0",
 						"192.0.2.0/24",
 					),
-					resource.TestCheckNoResourceAttr(
-						"data.nblists_list.test",
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length",
 						"list.1",
+						"2001:db8::/48",
 					),
-				),
-			},
-		},
-	})
-
-	// empty filter with allow_empty_filter=false
-	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConf + `
-data "nblists_list" "test" {
-	endpoint = "aggregates"
-}
-`,
-				ExpectError: regexp.MustCompile(`Error getting list: filter is nil or empty`),
-			},
-		},
-	})
 
-	// min violated
-	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConf + `
-data "nblists_list" "test" {
-	endpoint = "ip-addresses"
-	min = 2
-	filter = {
-		tag = ["6"]
-	}
-}
-`,
-				ExpectError: regexp.MustCompile(`The list has length \(1\) less than the min \(2\)`),
-			},
-		},
-	})
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.within",
+						"list.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.within",
+						"list.0",
+						"10.20.0.0/17",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.within",
+						"list.1",
+						"192.0.2.0/24",
+					),
 
-	// max violated
-	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.#",
+						"6",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.0",
+						"172.0.0.0/12",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.1",
+						"172.128.0.0/9",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.2",
+						"172.32.0.0/11",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.3",
+						"172.64.0.0/10",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.4",
+						"203.0.113.7/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"list.5",
+						"2606:4700::1/128",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.private.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.private.0",
+						"10.1.2.3/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.private.1",
+						"172.0.0.0/8",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.shared.0",
+						"100.64.0.1/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.link_local.0",
+						"fe80::1/128",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.documentation.0",
+						"203.0.113.7/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.bogon.#",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.special",
+						"special.%",
+						"5",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.%",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.0.0.0.0/0.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.0.0.0.0/0.0",
+						"192.0.2.0/27",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.0.0.0.0/0.1",
+						"192.0.2.200/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.::/0.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.::/0.0",
+						"2001:db8::/64",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.chunks",
+						"chunks.::/0.1",
+						"2001:db8::200/128",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.#",
+						"4",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.cidr",
+						"192.0.2.0/27",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.address",
+						"192.0.2.0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.prefix_length",
+						"27",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.family",
+						"4",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.is_single_ip",
+						"false",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.network",
+						"192.0.2.0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.broadcast",
+						"192.0.2.31",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.netmask",
+						"255.255.255.224",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.hostmask",
+						"0.0.0.31",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.0.num_addresses",
+						"32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.1.is_single_ip",
+						"true",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.2.family",
+						"6",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries.2.num_addresses",
+						"18446744073709551616",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries_by_cidr.%",
+						"4",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries",
+						"entries_by_cidr.2001:db8::200/128.netmask",
+						"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask",
+						"list_netmask.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask",
+						"list_netmask.0",
+						"192.0.2.0 255.255.255.224",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask",
+						"list_netmask.1",
+						"192.0.2.200 255.255.255.255",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask",
+						"list_wildcard.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask",
+						"list_wildcard.0",
+						"192.0.2.0 0.0.0.31",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask",
+						"list_wildcard.1",
+						"192.0.2.200 0.0.0.0",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask_cidr",
+						"list_netmask.#",
+						"4",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask_cidr",
+						"list_netmask.2",
+						"2001:db8::/64",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.netmask_cidr",
+						"list_wildcard.3",
+						"2001:db8::200/128",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize",
+						"list.#",
+						"3",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize",
+						"list.0",
+						"192.0.2.0/24",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize",
+						"list.1",
+						"198.51.100.1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize",
+						"list.2",
+						"2001:db8::/64",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize_host_route",
+						"list.#",
+						"4",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize_host_route",
+						"list.0",
+						"192.0.2.5/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize_host_route",
+						"list.1",
+						"192.0.2.9/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize_host_route",
+						"list.2",
+						"2001:db8::1/128",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.normalize_host_route",
+						"list.3",
+						"::ffff:198.51.100.1",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.expand",
+						"list.#",
+						"3",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.expand",
+						"expanded_list.#",
+						"9",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.expand",
+						"expanded_list.0",
+						"192.0.2.8",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.expand",
+						"expanded_list.7",
+						"192.0.2.15",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.expand",
+						"expanded_list.8",
+						"198.51.100.1",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list.#",
+						"6",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list.0",
+						"192.0.2.10/31",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list.1",
+						"192.0.2.12/30",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list.2",
+						"192.0.2.16/30",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list.3",
+						"192.0.2.20/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list.4",
+						"192.0.2.21/32",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list4.#",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list6.#",
+						"1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list6.0",
+						"2001:db8::/120",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list_ranges.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list_ranges.0",
+						"192.0.2.10-192.0.2.21",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ranges",
+						"list_ranges.1",
+						"2001:db8::-2001:db8::ff",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.family_counts",
+						"list.#",
+						"2",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.min_warn",
+						"list.#",
+						"1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.min_warn",
+						"list.0",
+						"192.0.2.6/32",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.must_contain",
+						"list.#",
+						"3",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"list.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"list.0",
+						"0.0.0.0/0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"list.1",
+						"::/0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"redundant.#",
+						"3",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"redundant.0",
+						"10.0.0.0/8",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"redundant.2",
+						"2001:db8::/48",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"overlaps.#",
+						"3",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"overlaps.0.covering",
+						"0.0.0.0/0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"overlaps.0.covered",
+						"10.0.0.0/8",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"overlaps.2.covering",
+						"::/0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.redundant",
+						"overlaps.2.covered",
+						"2001:db8::/48",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"entry_count",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"count4",
+						"3",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"count6",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"total_addresses4",
+						"4294967296",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"total_addresses6",
+						"340282366920938463463374607431768211456",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"largest_prefix",
+						"::/0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"smallest_prefix",
+						"192.0.2.0/24",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.stats",
+						"is_empty",
+						"false",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.two",
+						"entry_count",
+						"0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.two",
+						"is_empty",
+						"true",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.two",
+						"count4",
+						"0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.two",
+						"total_addresses6",
+						"0",
+					),
+					resource.TestCheckNoResourceAttr("data.nblists_list.two", "largest_prefix"),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.template",
+						"rendered",
+						"allow 10.20.0.0/22;\nallow 192.0.2.9/32;\nallow 198.51.100.0/24;\n",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.template_funcs",
+						"rendered",
+						"10.20.0.0 255.255.252.0 0.0.3.255 /22 v4|192.0.2.9 255.255.255.255 0.0.0.0 /32 v4|198.51.100.0 255.255.255.0 0.0.0.255 /24 v4|",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.format",
+						"rendered",
+						"create egress4 hash:net family inet -exist\nflush egress4\nadd egress4 10.20.0.0/22\nadd egress4 192.0.2.9/32\nadd egress4 198.51.100.0/24\ncreate egress6 hash:net family inet6 -exist\nflush egress6\n",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.format_prefix_list",
+						"rendered",
+						"ip prefix-list nblists seq 1497678845 permit 192.0.2.9/32\nip prefix-list nblists seq 1573923425 permit 198.51.100.0/24\nip prefix-list nblists seq 2317688395 permit 10.20.0.0/22 le 24\n",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.format_deny_all",
+						"rendered",
+						"allow 10.20.0.0/22;\nallow 192.0.2.9/32;\nallow 198.51.100.0/24;\ndeny all;\n",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ip_blocks",
+						"ip_blocks",
+						"[{\"ipBlock\":{\"cidr\":\"10.0.0.0/8\",\"except\":[\"10.20.0.0/22\"]}}]",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ip_blocks",
+						"cilium_cidr_set",
+						"[{\"cidr\":\"10.0.0.0/8\",\"except\":[\"10.20.0.0/22\"]}]",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.ip_blocks_except_prefixes",
+						"ip_blocks",
+						"[{\"ipBlock\":{\"cidr\":\"10.20.0.0/22\",\"except\":[\"10.20.1.0/24\"]}},{\"ipBlock\":{\"cidr\":\"192.0.2.9/32\"}},{\"ipBlock\":{\"cidr\":\"198.51.100.0/24\",\"except\":[\"198.51.100.128/25\"]}}]",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.format_rpsl",
+						"rendered",
+						"route:          10.20.0.0/22\norigin:         AS64500\nmnt-by:         MAINT-A\nmnt-by:         MAINT-B\nsource:         RIPE\n\nroute:          192.0.2.9/32\norigin:         AS64500\nmnt-by:         MAINT-A\nmnt-by:         MAINT-B\nsource:         RIPE\n\nroute:          198.51.100.0/24\norigin:         AS64500\nmnt-by:         MAINT-A\nmnt-by:         MAINT-B\nsource:         RIPE\n\nroute-set:      RS-EGRESS\nmembers:        10.20.0.0/22\nmembers:        192.0.2.9/32\nmembers:        198.51.100.0/24\nmnt-by:         MAINT-A\nmnt-by:         MAINT-B\nsource:         RIPE\n",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.reverse_dns",
+						"ptr_names.%",
+						"1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.reverse_dns",
+						"ptr_names.192.0.2.9",
+						"9.2.0.192.in-addr.arpa",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.reverse_dns",
+						"reverse_zones.#",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.reverse_dns",
+						"reverse_zones.0",
+						"0.20.10.in-addr.arpa",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.reverse_dns",
+						"reverse_zones.3",
+						"3.20.10.in-addr.arpa",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.reverse_dns",
+						"reverse_zones.4",
+						"100.51.198.in-addr.arpa",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length_warn",
+						"list.#",
+						"5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length_warn",
+						"list.0",
+						"0.0.0.0/0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length_warn",
+						"list.4",
+						"::/0",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries_by_cidr_masked",
+						"entries.#",
+						"4",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries_by_cidr_masked",
+						"entries_by_cidr.%",
+						"3",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries_by_cidr_masked",
+						"entries_by_cidr.192.0.2.0/24.address",
+						"192.0.2.5",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.entries_by_cidr_masked",
+						"entries_by_cidr.2001:db8::/64.address",
+						"2001:db8::1",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length_exclude",
+						"list.#",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length_exclude",
+						"list.0",
+						"192.0.2.0/24",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.prefix_length_exclude",
+						"list.1",
+						"2001:db8::/48",
+					),
+
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.no_cidr_only",
+						"list4.#",
+						"0",
+					),
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.no_cidr_only",
+						"list6.#",
+						"0",
+					),
+				),
+			},
+		},
+	})
+
+	// with allow_empty_filter=true
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "nblists" {
+    url = "%s"
+	token = "%s"
+	allow_empty_filter = true
+	request_timeout = 20
+}
+
+data "nblists_list" "test" {
+	endpoint = "aggregates"
+}
+`, url, token),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.nblists_list.test",
+						"list.0",
+						"192.0.2.0/24",
+					),
+					resource.TestCheckNoResourceAttr(
+						"data.nblists_list.test",
+						"list.1",
+					),
+				),
+			},
+		},
+	})
+
+	// empty filter with allow_empty_filter=false
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "aggregates"
+}
+`,
+				ExpectError: regexp.MustCompile(`Error getting list: filter is nil or empty`),
+			},
+		},
+	})
+
+	// min violated
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	min = 2
+	filter = {
+		tag = ["6"]
+	}
+}
+`,
+				ExpectError: regexp.MustCompile(`The list has length \(1\) less than the min \(2\)`),
+			},
+		},
+	})
+
+	// max violated
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	max = 1
+	filter = {
+		tag = ["10"]
+	}
+	summarize = false
+}
+`,
+				ExpectError: regexp.MustCompile(`The list has length \(2\) greater than the max \(1\)`),
+			},
+		},
+	})
+
+	// prefix length violated
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "aggregates"
+	filter = {
+		tag = ["broad"]
+	}
+	min_prefix_length4 = 16
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)configured limits:\s+0\.0\.0\.0/0, 10\.0\.0\.0/8`),
+			},
+		},
+	})
+
+	// prefix length violated by an entry split by exclude_special
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "aggregates"
+	filter = {
+		tag = ["broad"]
+	}
+	exclude_special = ["bogon"]
+	min_prefix_length4 = 8
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)configured limits:\s+0\.0\.0\.0/0`),
+			},
+		},
+	})
+
+	// IPv6 entries in mask notation
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "prefixes"
+	filter = {
+		tag = ["p1"]
+	}
+	summarize = false
+	mask_notation = true
+	mask_notation_ipv6 = "error"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)mask notation:\s+2001:db8::/64,\s+2001:db8::200/128`),
+			},
+		},
+	})
+
+	// conflicting normalize modes
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: providerConf + `
 data "nblists_list" "test" {
 	endpoint = "ip-addresses"
-	max = 1
 	filter = {
-		tag = ["10"]
+		tag = ["interfaces"]
+	}
+	normalize = ["mask_host_bits", "host_route"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid normalize modes`),
+			},
+		},
+	})
+
+	// expansion limits exceeded
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "aggregates"
+	filter = {
+		tag = ["broad"]
+	}
+	expand = true
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)more than 256 addresses:\s+0\.0\.0\.0/0,\s+10\.0\.0\.0/8`),
+			},
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "prefixes"
+	filter = {
+		tag = ["appliances"]
+	}
+	expand = true
+	expand_max_total = 8
+}
+`,
+				ExpectError: regexp.MustCompile(`would produce 9 addresses`),
+			},
+		},
+	})
+
+	// invalid range
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "ip-ranges"
+	filter = {
+		tag = ["invalid"]
+	}
+	split_af = true
+}
+`,
+				ExpectError: regexp.MustCompile(`192\.0\.2\.20 is greater than 192\.0\.2\.10`),
+			},
+		},
+	})
+
+	// per-family counts violated
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["11"]
 	}
 	summarize = false
+	as_cidr = false
+	min4 = 2
 }
 `,
-				ExpectError: regexp.MustCompile(`The list has length \(2\) greater than the max \(1\)`),
+				ExpectError: regexp.MustCompile(`The list has 1 IPv4 entries, less than the min4 \(2\)`),
+			},
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["11"]
+	}
+	summarize = false
+	as_cidr = false
+	max6 = 0
+}
+`,
+				ExpectError: regexp.MustCompile(`The list has 1 IPv6 entries, greater than the max6 \(0\)`),
+			},
+		},
+	})
+
+	// invalid template
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "prefixes"
+	filter = {
+		tag = ["corp-egress"]
+	}
+	template = "{{range .List}"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid template`),
+			},
+		},
+	})
+
+	// template and format are mutually exclusive
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "prefixes"
+	filter = {
+		tag = ["corp-egress"]
+	}
+	template = "{{.List}}"
+	format = "ipset"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+
+	// rpsl requires origin, mnt-by and source
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "prefixes"
+	filter = {
+		tag = ["corp-egress"]
+	}
+	format = "rpsl"
+	rpsl_mnt_by = ["MAINT-EXAMPLE"]
+	rpsl_source = "RIPE"
+}
+`,
+				ExpectError: regexp.MustCompile(`rpsl_origin is required when format is "rpsl"`),
+			},
+		},
+	})
+
+	// must_contain violated
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_list" "test" {
+	endpoint = "prefixes"
+	filter = {
+		tag = ["corp-egress"]
+	}
+	must_contain = ["10.20.1.5", "192.0.2.10", "2001:db8::1"]
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)must_contain:\s+192\.0\.2\.10/32,\s+2001:db8::1/128`),
+			},
+		},
+	})
+
+	// baseline
+	baselineFile := filepath.Join(t.TempDir(), "baseline.json")
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["10"]
+	}
+	summarize = false
+	baseline_file = %q
+	max_removed = 1
+}
+`, baselineFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nblists_list.test", "list.#", "2"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "added.#", "2"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "removed.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["1"]
+	}
+	baseline_file = %q
+	max_removed = 1
+}
+`, baselineFile),
+				ExpectError: regexp.MustCompile(`2 entries were removed since the baseline`),
+			},
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["1"]
+	}
+	baseline_file = %q
+	max_removed = 1
+	accept_changes = true
+}
+`, baselineFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nblists_list.test", "list.#", "1"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "added.#", "1"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "added.0", "192.0.2.1/32"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "removed.#", "2"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "removed.0", "192.0.2.10/32"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["1"]
+	}
+	baseline_file = %q
+	max_removed = 1
+}
+`, baselineFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nblists_list.test", "added.#", "0"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "removed.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["10"]
+	}
+	summarize = false
+	baseline_file = %q
+	max_added = 1
+	on_violation = "warn"
+}
+`, baselineFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nblists_list.test", "list.#", "2"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "added.#", "2"),
+				),
+			},
+			// the baseline is not updated when the violation is only a warning
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["10"]
+	}
+	summarize = false
+	baseline_file = %q
+	max_added = 1
+}
+`, baselineFile),
+				ExpectError: regexp.MustCompile(`2 entries were added since the baseline`),
+			},
+			// changes within the limits are not accepted automatically
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["10"]
+	}
+	summarize = false
+	baseline_file = %q
+	max_added = 2
+}
+`, baselineFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nblists_list.test", "added.#", "2"),
+					resource.TestCheckResourceAttr("data.nblists_list.test", "removed.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(providerConf+`
+data "nblists_list" "test" {
+	endpoint = "ip-addresses"
+	filter = {
+		tag = ["10"]
+	}
+	summarize = false
+	baseline_file = %q
+	max_added = 1
+}
+`, baselineFile),
+				ExpectError: regexp.MustCompile(`2 entries were added since the baseline`),
 			},
 		},
 	})
 }
+
+func TestAddViolation(t *testing.T) {
+	tests := map[string]struct {
+		onViolation types.String
+		wantError   bool
+	}{
+		"default": {onViolation: types.StringNull(), wantError: true},
+		"error":   {onViolation: types.StringValue(onViolationError), wantError: true},
+		"drop":    {onViolation: types.StringValue(onViolationDrop), wantError: true},
+		"warn":    {onViolation: types.StringValue(onViolationWarn)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := ListDataSourceModel{OnViolation: tc.onViolation}
+			var diags diag.Diagnostics
+			m.addViolation(&diags, "summary", "detail")
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+			}
+			if diags.HasError() != tc.wantError {
+				t.Errorf("got error=%v, want %v", diags.HasError(), tc.wantError)
+			}
+			if diags[0].Summary() != "summary" || diags[0].Detail() != "detail" {
+				t.Errorf("got %q/%q, want summary/detail", diags[0].Summary(), diags[0].Detail())
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// listEntry is a parsed element of a list.
+type listEntry struct {
+	// raw is the element as returned by NetBox.
+	// It is empty for entries derived while processing the list.
+	raw    string
+	prefix netip.Prefix
+	// noCIDR is true if the element is an address without a prefix length.
+	noCIDR bool
+}
+
+// String returns the element as returned by NetBox, or the prefix in CIDR
+// notation if the entry was derived.
+func (e listEntry) String() string {
+	if e.raw != "" {
+		return e.raw
+	}
+	return e.prefix.String()
+}
+
+// NoCIDRString returns the entry without the prefix length if it is a single IP.
+func (e listEntry) NoCIDRString() string {
+	if !e.noCIDR && e.prefix.IsSingleIP() {
+		return e.prefix.Addr().String()
+	}
+	return e.String()
+}
+
+// NetmaskString returns the network address and netmask of the entry,
+// e.g. 192.0.2.0 255.255.255.0.
+func (e listEntry) NetmaskString() string {
+	return e.prefix.Masked().Addr().String() + " " + prefixNetmask(e.prefix).String()
+}
+
+// WildcardString returns the network address and wildcard mask of the entry,
+// e.g. 192.0.2.0 0.0.0.255.
+func (e listEntry) WildcardString() string {
+	return e.prefix.Masked().Addr().String() + " " + prefixHostmask(e.prefix).String()
+}
+
+// entryDetails describes an entry in the entries output.
+type entryDetails struct {
+	CIDR         string `tfsdk:"cidr"`
+	Address      string `tfsdk:"address"`
+	PrefixLength int64  `tfsdk:"prefix_length"`
+	Family       int64  `tfsdk:"family"`
+	IsSingleIP   bool   `tfsdk:"is_single_ip"`
+	Network      string `tfsdk:"network"`
+	Broadcast    string `tfsdk:"broadcast"`
+	Netmask      string `tfsdk:"netmask"`
+	Hostmask     string `tfsdk:"hostmask"`
+	NumAddresses string `tfsdk:"num_addresses"`
+}
+
+// Details returns the details of the entry.
+func (e listEntry) Details() entryDetails {
+	family := int64(6)
+	if e.prefix.Addr().Is4() {
+		family = 4
+	}
+	return entryDetails{
+		CIDR:         e.prefix.String(),
+		Address:      e.prefix.Addr().String(),
+		PrefixLength: int64(e.prefix.Bits()),
+		Family:       family,
+		IsSingleIP:   e.prefix.IsSingleIP(),
+		Network:      e.prefix.Masked().Addr().String(),
+		Broadcast:    lastAddr(e.prefix).String(),
+		Netmask:      prefixNetmask(e.prefix).String(),
+		Hostmask:     prefixHostmask(e.prefix).String(),
+		NumAddresses: numAddresses(e.prefix).String(),
+	}
+}
+
+// parseEntryError is returned by parseEntries when an element
+// could not be parsed.
+type parseEntryError struct {
+	summary string
+	detail  string
+}
+
+func (e *parseEntryError) Error() string {
+	return e.detail
+}
+
+// parseEntries parses each element of list as an IP address or prefix.
+func parseEntries(list []string) ([]listEntry, error) {
+	ret := make([]listEntry, 0, len(list))
+	for _, e := range list {
+		if first, last, ok := strings.Cut(e, "-"); ok {
+			prefixes, err := parseRange(first, last)
+			if err != nil {
+				return nil, &parseEntryError{
+					summary: "Error parsing IP range",
+					detail:  fmt.Sprintf("Error parsing %q: %v", e, err),
+				}
+			}
+			for _, p := range prefixes {
+				ret = append(ret, listEntry{prefix: p})
+			}
+		} else if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, &parseEntryError{
+					summary: "Error parsing IP/prefix",
+					detail:  fmt.Sprintf("Error parsing %q: %v", e, err),
+				}
+			}
+			ret = append(ret, listEntry{raw: e, prefix: p})
+		} else {
+			p, err := parsePrefix(e)
+			if err != nil {
+				return nil, &parseEntryError{
+					summary: "Error parsing IP",
+					detail:  fmt.Sprintf("Error parsing %q: %v", e, err),
+				}
+			}
+			ret = append(ret, listEntry{raw: e, prefix: p, noCIDR: true})
+		}
+	}
+	return ret, nil
+}
+
+// expandLimitError is returned by expandEntries when a limit is exceeded.
+type expandLimitError struct {
+	summary string
+	detail  string
+}
+
+func (e *expandLimitError) Error() string {
+	return e.detail
+}
+
+// expandEntries returns every address covered by entries, sorted by address.
+// The limits are checked before any address is enumerated.
+func expandEntries(entries []listEntry, maxPrefixSize int64, maxTotal int64) ([]string, error) {
+	var tooLarge []string
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		if numAddresses(e.prefix).Cmp(big.NewInt(maxPrefixSize)) > 0 {
+			tooLarge = append(tooLarge, e.String())
+		}
+		prefixes = append(prefixes, e.prefix)
+	}
+	if len(tooLarge) > 0 {
+		return nil, &expandLimitError{
+			summary: "Prefix too large to expand",
+			detail: fmt.Sprintf(
+				"The following entries contain more than %d addresses: %s",
+				maxPrefixSize, strings.Join(tooLarge, ", "),
+			),
+		}
+	}
+
+	prefixes = aggregatePrefixes(prefixes)
+	total := new(big.Int)
+	for _, p := range prefixes {
+		total.Add(total, numAddresses(p))
+	}
+	if total.Cmp(big.NewInt(maxTotal)) > 0 {
+		return nil, &expandLimitError{
+			summary: "Expansion limit exceeded",
+			detail:  fmt.Sprintf("Expanding the list would produce %s addresses, more than the limit of %d.", total, maxTotal),
+		}
+	}
+
+	ret := make([]string, 0, total.Int64())
+	for _, p := range prefixes {
+		for _, a := range expandPrefix(p) {
+			ret = append(ret, a.String())
+		}
+	}
+	return ret, nil
+}
+
+// withPrefix returns the entry with its prefix replaced by p.
+// Entries without a prefix length keep that format if p is a single IP.
+func (e listEntry) withPrefix(p netip.Prefix) listEntry {
+	if p == e.prefix {
+		return e
+	}
+	ret := listEntry{prefix: p, noCIDR: e.noCIDR && p.IsSingleIP()}
+	if ret.noCIDR {
+		ret.raw = p.Addr().String()
+	}
+	return ret
+}
+
+// normalizeEntries applies the normalize modes to entries.
+func normalizeEntries(entries []listEntry, modes map[string]bool) []listEntry {
+	ret := make([]listEntry, 0, len(entries))
+	for _, e := range entries {
+		p := e.prefix
+		if modes[normalizeUnmapIPv4] && p.Addr().Is4In6() && p.Bits() >= 96 {
+			p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+		}
+		if modes[normalizeMaskHostBits] {
+			p = p.Masked()
+		}
+		if modes[normalizeHostRoute] {
+			p = netip.PrefixFrom(p.Addr(), p.Addr().BitLen())
+		}
+		e = e.withPrefix(p)
+		if modes[normalizeCanonical] && e.raw != "" {
+			e.raw = p.String()
+			if e.noCIDR {
+				e.raw = p.Addr().String()
+			}
+		}
+		ret = append(ret, e)
+	}
+	return ret
+}
+
+// parseRange parses the range from first to last and returns the
+// minimal list of prefixes covering it.
+func parseRange(first, last string) ([]netip.Prefix, error) {
+	start, err := netip.ParseAddr(strings.TrimSpace(first))
+	if err != nil {
+		return nil, err
+	}
+	end, err := netip.ParseAddr(strings.TrimSpace(last))
+	if err != nil {
+		return nil, err
+	}
+	start, end = start.WithZone(""), end.WithZone("")
+	if start.Is4() != end.Is4() {
+		return nil, fmt.Errorf("%s and %s are of different address families", start, end)
+	}
+	if start.Compare(end) > 0 {
+		return nil, fmt.Errorf("%s is greater than %s", start, end)
+	}
+	return rangePrefixes(start, end), nil
+}
+
+// entryRanges returns the minimal sorted list of ranges covering entries.
+func entryRanges(entries []listEntry) []string {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		prefixes = append(prefixes, e.prefix)
+	}
+	ranges := prefixRanges(prefixes)
+	ret := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		ret = append(ret, r.String())
+	}
+	return ret
+}
+
+// sortEntries sorts entries by their string representation.
+func sortEntries(entries []listEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].String() < entries[j].String()
+	})
+}
+
+// uniqueEntries removes consecutive entries with the same string representation.
+func uniqueEntries(sorted []listEntry) []listEntry {
+	ret := make([]listEntry, 0, len(sorted))
+	for i, e := range sorted {
+		if i > 0 && e.String() == sorted[i-1].String() {
+			continue
+		}
+		ret = append(ret, e)
+	}
+	return ret
+}
+
+// entryStrings returns the string representation of each entry.
+func entryStrings(entries []listEntry) []string {
+	ret := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, e.String())
+	}
+	return ret
+}
+
+// countFamilies returns the number of IPv4 and IPv6 entries.
+func countFamilies(entries []listEntry) (int, int) {
+	var count4, count6 int
+	for _, e := range entries {
+		if e.prefix.Addr().Is4() {
+			count4++
+		} else {
+			count6++
+		}
+	}
+	return count4, count6
+}
+
+// entriesByCIDR returns the details of entries keyed by their masked prefix.
+// Of entries with the same masked prefix, the first one wins.
+func entriesByCIDR(entries []listEntry) map[string]entryDetails {
+	ret := make(map[string]entryDetails, len(entries))
+	for _, e := range entries {
+		key := e.prefix.Masked().String()
+		if _, ok := ret[key]; !ok {
+			ret[key] = e.Details()
+		}
+	}
+	return ret
+}
+
+// excludeEntries removes the address space of exclude from entries.
+// Entries covered by an exclusion are dropped and entries
+// covering an exclusion are split into the remaining prefixes.
+func excludeEntries(entries []listEntry, exclude []netip.Prefix) []listEntry {
+	exclude = aggregatePrefixes(exclude)
+	ret := make([]listEntry, 0, len(entries))
+	for _, e := range entries {
+		if len(overlappingPrefixes(exclude, e.prefix)) == 0 {
+			ret = append(ret, e)
+			continue
+		}
+		for _, p := range excludePrefixes(e.prefix, exclude) {
+			ret = append(ret, listEntry{prefix: p})
+		}
+	}
+	return ret
+}
+
+// prefixLengthLimits are the inclusive prefix length limits for each address family.
+type prefixLengthLimits struct {
+	min4, max4 int
+	min6, max6 int
+}
+
+// filterPrefixLength splits entries into those within limits and those outside.
+func filterPrefixLength(entries []listEntry, limits prefixLengthLimits) ([]listEntry, []listEntry) {
+	ok := make([]listEntry, 0, len(entries))
+	var violations []listEntry
+	for _, e := range entries {
+		lower, upper := limits.min6, limits.max6
+		if e.prefix.Addr().Is4() {
+			lower, upper = limits.min4, limits.max4
+		}
+		if e.prefix.Bits() < lower || e.prefix.Bits() > upper {
+			violations = append(violations, e)
+		} else {
+			ok = append(ok, e)
+		}
+	}
+	return ok, violations
+}
+
+// containingPrefix reports whether a prefix in sorted contains p.
+// If not, it returns the prefixes in sorted that are within p.
+// sorted must be the output of aggregatePrefixes.
+func containingPrefix(sorted []netip.Prefix, p netip.Prefix) ([]netip.Prefix, bool) {
+	overlapping := overlappingPrefixes(sorted, p)
+	for _, o := range overlapping {
+		if o.Bits() <= p.Bits() && o.Contains(p.Addr()) {
+			return nil, true
+		}
+	}
+	return overlapping, false
+}
+
+// missingPrefixes returns the prefixes of required that are not covered by entries.
+func missingPrefixes(entries []listEntry, required []netip.Prefix) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		prefixes = append(prefixes, e.prefix)
+	}
+	prefixes = aggregatePrefixes(prefixes)
+
+	var ret []netip.Prefix
+	for _, r := range required {
+		if _, ok := containingPrefix(prefixes, r); !ok {
+			ret = append(ret, r)
+		}
+	}
+	sortPrefixes(ret)
+	return ret
+}
+
+// filterWithin returns the entries within a prefix of within.
+// Entries only partially within are handled according to partial.
+func filterWithin(entries []listEntry, within []netip.Prefix, partial string) []listEntry {
+	within = aggregatePrefixes(within)
+	ret := make([]listEntry, 0, len(entries))
+	for _, e := range entries {
+		overlapping, contained := containingPrefix(within, e.prefix)
+		switch {
+		case contained:
+			ret = append(ret, e)
+		case len(overlapping) == 0:
+		case partial == partialOverlapKeep:
+			ret = append(ret, e)
+		case partial == partialOverlapClip:
+			for _, p := range overlapping {
+				ret = append(ret, listEntry{prefix: p})
+			}
+		}
+	}
+	return ret
+}
+
+// filterNotWithin returns the entries not within a prefix of notWithin.
+// Entries only partially within are handled according to partial.
+func filterNotWithin(entries []listEntry, notWithin []netip.Prefix, partial string) []listEntry {
+	notWithin = aggregatePrefixes(notWithin)
+	ret := make([]listEntry, 0, len(entries))
+	for _, e := range entries {
+		overlapping, contained := containingPrefix(notWithin, e.prefix)
+		switch {
+		case contained:
+		case len(overlapping) == 0:
+			ret = append(ret, e)
+		case partial == partialOverlapKeep:
+			ret = append(ret, e)
+		case partial == partialOverlapClip:
+			for _, p := range excludePrefixes(e.prefix, overlapping) {
+				ret = append(ret, listEntry{prefix: p})
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,531 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEntries(t *testing.T) {
+	tests := map[string]struct {
+		in          []string
+		wantNoCIDR  []string
+		wantSummary string
+	}{
+		"mixed": {
+			in:         []string{"192.0.2.0/27", "192.0.2.200/32", "2001:db8::12"},
+			wantNoCIDR: []string{"192.0.2.0/27", "192.0.2.200", "2001:db8::12"},
+		},
+		"invalid prefix": {
+			in:          []string{"192.0.2.0/33"},
+			wantSummary: "Error parsing IP/prefix",
+		},
+		"invalid IP": {
+			in:          []string{"192.0.2"},
+			wantSummary: "Error parsing IP",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := parseEntries(tc.in)
+			if tc.wantSummary != "" {
+				pe, ok := err.(*parseEntryError)
+				if !ok {
+					t.Fatalf("expected a *parseEntryError, got %v", err)
+				}
+				if pe.summary != tc.wantSummary {
+					t.Errorf("got summary %q, want %q", pe.summary, tc.wantSummary)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if s := entryStrings(have); !reflect.DeepEqual(s, tc.in) {
+				t.Errorf("got list %v, want %v", s, tc.in)
+			}
+			noCIDR := make([]string, 0, len(have))
+			for _, e := range have {
+				noCIDR = append(noCIDR, e.NoCIDRString())
+			}
+			if !reflect.DeepEqual(noCIDR, tc.wantNoCIDR) {
+				t.Errorf("got no CIDR list %v, want %v", noCIDR, tc.wantNoCIDR)
+			}
+		})
+	}
+}
+
+func TestParseRangeEntries(t *testing.T) {
+	tests := map[string]struct {
+		in         []string
+		want       []string
+		wantRanges []string
+		wantError  bool
+	}{
+		"ranges": {
+			in:         []string{"192.0.2.10-192.0.2.13", "192.0.2.14/31", "2001:db8::1 - 2001:db8::2"},
+			want:       []string{"192.0.2.10/31", "192.0.2.12/31", "192.0.2.14/31", "2001:db8::1/128", "2001:db8::2/128"},
+			wantRanges: []string{"192.0.2.10-192.0.2.15", "2001:db8::1-2001:db8::2"},
+		},
+		"reversed": {
+			in:        []string{"192.0.2.10-192.0.2.1"},
+			wantError: true,
+		},
+		"mixed families": {
+			in:        []string{"192.0.2.1-2001:db8::1"},
+			wantError: true,
+		},
+		"invalid": {
+			in:        []string{"192.0.2.1-192.0.2"},
+			wantError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := parseEntries(tc.in)
+			if tc.wantError {
+				pe, ok := err.(*parseEntryError)
+				if !ok {
+					t.Fatalf("expected a *parseEntryError, got %v", err)
+				}
+				if pe.summary != "Error parsing IP range" {
+					t.Errorf("got summary %q", pe.summary)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if s := entryStrings(have); !reflect.DeepEqual(s, tc.want) {
+				t.Errorf("got %v, want %v", s, tc.want)
+			}
+			if r := entryRanges(have); !reflect.DeepEqual(r, tc.wantRanges) {
+				t.Errorf("got ranges %v, want %v", r, tc.wantRanges)
+			}
+		})
+	}
+}
+
+func TestExcludeEntries(t *testing.T) {
+	tests := map[string]struct {
+		in      []string
+		exclude []string
+		want    []string
+	}{
+		"untouched entries keep their format": {
+			in:      []string{"192.0.2.4", "2001:DB8::1/128"},
+			exclude: []string{"198.51.100.0/24"},
+			want:    []string{"192.0.2.4", "2001:DB8::1/128"},
+		},
+		"covered entries are dropped": {
+			in:      []string{"192.0.2.4", "192.0.2.64/26", "198.51.100.1/32"},
+			exclude: []string{"192.0.2.0/24"},
+			want:    []string{"198.51.100.1/32"},
+		},
+		"covering entries are split": {
+			in:      []string{"192.0.2.0/24", "198.51.100.1/32"},
+			exclude: []string{"192.0.2.0/25", "192.0.2.128/26"},
+			want:    []string{"192.0.2.192/26", "198.51.100.1/32"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			have := excludeEntries(entries, mustParsePrefixes(t, tc.exclude))
+			sortEntries(have)
+			have = uniqueEntries(have)
+			if s := entryStrings(have); !reflect.DeepEqual(s, tc.want) {
+				t.Errorf("got %v, want %v", s, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterPrefixLength(t *testing.T) {
+	entries, err := parseEntries([]string{"0.0.0.0/0", "10.0.0.0/8", "192.0.2.0/24", "192.0.2.1", "::/0", "2001:db8::/48", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	tests := map[string]struct {
+		limits         prefixLengthLimits
+		wantOK         []string
+		wantViolations []string
+	}{
+		"no limits": {
+			limits: prefixLengthLimits{min4: 0, max4: 32, min6: 0, max6: 128},
+			wantOK: []string{"0.0.0.0/0", "10.0.0.0/8", "192.0.2.0/24", "192.0.2.1", "::/0", "2001:db8::/48", "2001:db8::1"},
+		},
+		"min": {
+			limits:         prefixLengthLimits{min4: 16, max4: 32, min6: 32, max6: 128},
+			wantOK:         []string{"192.0.2.0/24", "192.0.2.1", "2001:db8::/48", "2001:db8::1"},
+			wantViolations: []string{"0.0.0.0/0", "10.0.0.0/8", "::/0"},
+		},
+		"max": {
+			limits:         prefixLengthLimits{min4: 0, max4: 24, min6: 0, max6: 64},
+			wantOK:         []string{"0.0.0.0/0", "10.0.0.0/8", "192.0.2.0/24", "::/0", "2001:db8::/48"},
+			wantViolations: []string{"192.0.2.1", "2001:db8::1"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, violations := filterPrefixLength(entries, tc.limits)
+			if s := entryStrings(ok); !reflect.DeepEqual(s, tc.wantOK) {
+				t.Errorf("got %v, want %v", s, tc.wantOK)
+			}
+			if len(violations) > 0 || len(tc.wantViolations) > 0 {
+				if s := entryStrings(violations); !reflect.DeepEqual(s, tc.wantViolations) {
+					t.Errorf("got violations %v, want %v", s, tc.wantViolations)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingPrefixes(t *testing.T) {
+	tests := map[string]struct {
+		in       []string
+		required []string
+		want     []string
+	}{
+		"contained": {
+			in:       []string{"10.20.0.0/22", "192.0.2.9"},
+			required: []string{"10.20.1.5", "10.20.2.0/24", "192.0.2.9/32"},
+			want:     []string{},
+		},
+		"missing": {
+			in:       []string{"10.20.0.0/22", "192.0.2.9"},
+			required: []string{"2001:db8::1", "10.20.0.0/16", "192.0.2.10"},
+			want:     []string{"10.20.0.0/16", "192.0.2.10/32", "2001:db8::1/128"},
+		},
+		"covered by adjacent entries": {
+			in:       []string{"192.0.2.0/25", "192.0.2.128/25"},
+			required: []string{"192.0.2.0/24"},
+			want:     []string{},
+		},
+		"empty list": {
+			in:       []string{},
+			required: []string{"0.0.0.0/0"},
+			want:     []string{"0.0.0.0/0"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			have := prefixStrings(missingPrefixes(entries, mustParsePrefixes(t, tc.required)))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterWithin(t *testing.T) {
+	tests := map[string]struct {
+		in        []string
+		within    []string
+		notWithin []string
+		partial   string
+		want      []string
+	}{
+		"within": {
+			in:      []string{"10.20.1.1", "10.30.1.1/32", "10.20.0.0/16", "2001:db8::1"},
+			within:  []string{"10.20.0.0/16"},
+			partial: partialOverlapDrop,
+			want:    []string{"10.20.0.0/16", "10.20.1.1"},
+		},
+		"within partial keep": {
+			in:      []string{"10.0.0.0/8", "192.0.2.1"},
+			within:  []string{"10.20.0.0/16"},
+			partial: partialOverlapKeep,
+			want:    []string{"10.0.0.0/8"},
+		},
+		"within partial clip": {
+			in:      []string{"10.0.0.0/8", "192.0.2.1"},
+			within:  []string{"10.20.0.0/16", "10.30.0.0/16"},
+			partial: partialOverlapClip,
+			want:    []string{"10.20.0.0/16", "10.30.0.0/16"},
+		},
+		"within partial drop": {
+			in:      []string{"10.0.0.0/8", "10.20.1.0/24"},
+			within:  []string{"10.20.0.0/16"},
+			partial: partialOverlapDrop,
+			want:    []string{"10.20.1.0/24"},
+		},
+		"not within": {
+			in:        []string{"10.20.1.1", "10.30.1.1/32", "2001:db8::1"},
+			notWithin: []string{"10.20.0.0/16"},
+			partial:   partialOverlapDrop,
+			want:      []string{"10.30.1.1/32", "2001:db8::1"},
+		},
+		"not within partial keep": {
+			in:        []string{"10.0.0.0/8"},
+			notWithin: []string{"10.20.0.0/16"},
+			partial:   partialOverlapKeep,
+			want:      []string{"10.0.0.0/8"},
+		},
+		"not within partial clip": {
+			in:        []string{"192.0.2.0/24"},
+			notWithin: []string{"192.0.2.0/25"},
+			partial:   partialOverlapClip,
+			want:      []string{"192.0.2.128/25"},
+		},
+		"clipped duplicates": {
+			in:      []string{"0.0.0.0/0", "10.0.0.0/8", "10.20.0.0/16"},
+			within:  []string{"10.20.0.0/16"},
+			partial: partialOverlapClip,
+			want:    []string{"10.20.0.0/16"},
+		},
+		"not within partial drop": {
+			in:        []string{"192.0.2.0/24", "198.51.100.0/24"},
+			notWithin: []string{"192.0.2.0/25"},
+			partial:   partialOverlapDrop,
+			want:      []string{"198.51.100.0/24"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if tc.within != nil {
+				have = filterWithin(have, mustParsePrefixes(t, tc.within), tc.partial)
+			}
+			if tc.notWithin != nil {
+				have = filterNotWithin(have, mustParsePrefixes(t, tc.notWithin), tc.partial)
+			}
+			sortEntries(have)
+			have = uniqueEntries(have)
+			if s := entryStrings(have); !reflect.DeepEqual(s, tc.want) {
+				t.Errorf("got %v, want %v", s, tc.want)
+			}
+		})
+	}
+}
+
+func TestEntryDetails(t *testing.T) {
+	entries, err := parseEntries([]string{"192.0.2.5/24", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	want := []entryDetails{
+		{
+			CIDR:         "192.0.2.5/24",
+			Address:      "192.0.2.5",
+			PrefixLength: 24,
+			Family:       4,
+			Network:      "192.0.2.0",
+			Broadcast:    "192.0.2.255",
+			Netmask:      "255.255.255.0",
+			Hostmask:     "0.0.0.255",
+			NumAddresses: "256",
+		},
+		{
+			CIDR:         "2001:db8::1/128",
+			Address:      "2001:db8::1",
+			PrefixLength: 128,
+			Family:       6,
+			IsSingleIP:   true,
+			Network:      "2001:db8::1",
+			Broadcast:    "2001:db8::1",
+			Netmask:      "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+			Hostmask:     "::",
+			NumAddresses: "1",
+		},
+	}
+	for i, e := range entries {
+		if have := e.Details(); !reflect.DeepEqual(have, want[i]) {
+			t.Errorf("got %+v, want %+v", have, want[i])
+		}
+	}
+}
+
+func TestMaskStrings(t *testing.T) {
+	tests := map[string]struct {
+		netmask  string
+		wildcard string
+	}{
+		"192.0.2.0/24": {netmask: "192.0.2.0 255.255.255.0", wildcard: "192.0.2.0 0.0.0.255"},
+		"192.0.2.5/24": {netmask: "192.0.2.0 255.255.255.0", wildcard: "192.0.2.0 0.0.0.255"},
+		"192.0.2.1":    {netmask: "192.0.2.1 255.255.255.255", wildcard: "192.0.2.1 0.0.0.0"},
+		"0.0.0.0/0":    {netmask: "0.0.0.0 0.0.0.0", wildcard: "0.0.0.0 255.255.255.255"},
+	}
+
+	for s, tc := range tests {
+		t.Run(s, func(t *testing.T) {
+			entries, err := parseEntries([]string{s})
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if have := entries[0].NetmaskString(); have != tc.netmask {
+				t.Errorf("got %q, want %q", have, tc.netmask)
+			}
+			if have := entries[0].WildcardString(); have != tc.wildcard {
+				t.Errorf("got %q, want %q", have, tc.wildcard)
+			}
+		})
+	}
+}
+
+func TestNormalizeEntries(t *testing.T) {
+	tests := map[string]struct {
+		in    []string
+		modes []string
+		want  []string
+	}{
+		"mask host bits": {
+			in:    []string{"192.0.2.5/24", "192.0.2.0/24", "192.0.2.1", "2001:db8::1/64"},
+			modes: []string{normalizeMaskHostBits},
+			want:  []string{"192.0.2.0/24", "192.0.2.1", "2001:db8::/64"},
+		},
+		"host route": {
+			in:    []string{"192.0.2.5/24", "192.0.2.1", "2001:db8::1/64"},
+			modes: []string{normalizeHostRoute},
+			want:  []string{"192.0.2.1", "192.0.2.5/32", "2001:db8::1/128"},
+		},
+		"unmap IPv4": {
+			in:    []string{"::ffff:192.0.2.1", "::ffff:192.0.2.0/120", "::ffff:0:0/95", "2001:db8::1"},
+			modes: []string{normalizeUnmapIPv4},
+			want:  []string{"192.0.2.0/24", "192.0.2.1", "2001:db8::1", "::ffff:0:0/95"},
+		},
+		"canonical": {
+			in:    []string{"2001:DB8:0:0::1", "2001:0db8::/32", "192.0.2.1/32"},
+			modes: []string{normalizeCanonical},
+			want:  []string{"192.0.2.1/32", "2001:db8::/32", "2001:db8::1"},
+		},
+		"combined": {
+			in:    []string{"::FFFF:192.0.2.5/120", "2001:DB8::1/64"},
+			modes: []string{normalizeUnmapIPv4, normalizeMaskHostBits, normalizeCanonical},
+			want:  []string{"192.0.2.0/24", "2001:db8::/64"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			modes := map[string]bool{}
+			for _, m := range tc.modes {
+				modes[m] = true
+			}
+			have := normalizeEntries(entries, modes)
+			sortEntries(have)
+			have = uniqueEntries(have)
+			if s := entryStrings(have); !reflect.DeepEqual(s, tc.want) {
+				t.Errorf("got %v, want %v", s, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandEntries(t *testing.T) {
+	tests := map[string]struct {
+		in          []string
+		maxSize     int64
+		maxTotal    int64
+		want        []string
+		wantSummary string
+	}{
+		"expanded": {
+			in:       []string{"192.0.2.8/30", "192.0.2.9", "2001:db8::/127", "192.0.2.1"},
+			maxSize:  4,
+			maxTotal: 7,
+			want:     []string{"192.0.2.1", "192.0.2.8", "192.0.2.9", "192.0.2.10", "192.0.2.11", "2001:db8::", "2001:db8::1"},
+		},
+		"host bits": {
+			in:       []string{"192.0.2.5/31"},
+			maxSize:  2,
+			maxTotal: 2,
+			want:     []string{"192.0.2.4", "192.0.2.5"},
+		},
+		"prefix too large": {
+			in:          []string{"192.0.2.0/29", "10.0.0.0/8"},
+			maxSize:     4,
+			maxTotal:    1 << 30,
+			wantSummary: "Prefix too large to expand",
+		},
+		"IPv6 prefix too large": {
+			in:          []string{"::/0"},
+			maxSize:     256,
+			maxTotal:    256,
+			wantSummary: "Prefix too large to expand",
+		},
+		"total too large": {
+			in:          []string{"192.0.2.0/29", "198.51.100.0/29"},
+			maxSize:     8,
+			maxTotal:    15,
+			wantSummary: "Expansion limit exceeded",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			have, err := expandEntries(entries, tc.maxSize, tc.maxTotal)
+			if tc.wantSummary != "" {
+				le, ok := err.(*expandLimitError)
+				if !ok {
+					t.Fatalf("expected an *expandLimitError, got %v", err)
+				}
+				if le.summary != tc.wantSummary {
+					t.Errorf("got summary %q, want %q", le.summary, tc.wantSummary)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountFamilies(t *testing.T) {
+	entries, err := parseEntries([]string{"192.0.2.1", "192.0.2.0/24", "2001:db8::1", "::ffff:192.0.2.1"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	count4, count6 := countFamilies(entries)
+	if count4 != 2 || count6 != 2 {
+		t.Errorf("got %d IPv4 and %d IPv6 entries, want 2 and 2", count4, count6)
+	}
+}
+
+func TestEntriesByCIDR(t *testing.T) {
+	entries, err := parseEntries([]string{"192.0.2.1", "192.0.2.1/32", "192.0.2.5/24", "192.0.2.9/24", "2001:db8::1/64"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	have := map[string]string{}
+	for k, v := range entriesByCIDR(entries) {
+		have[k] = v.Address
+	}
+	want := map[string]string{
+		"192.0.2.1/32":  "192.0.2.1",
+		"192.0.2.0/24":  "192.0.2.5",
+		"2001:db8::/64": "2001:db8::1",
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("expected %v but got %v", want, have)
+	}
+}
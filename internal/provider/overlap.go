@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"sort"
+)
+
+// entryOverlap is a pair of entries where covering contains covered.
+type entryOverlap struct {
+	Covering string `tfsdk:"covering"`
+	Covered  string `tfsdk:"covered"`
+}
+
+// findOverlaps returns every pair of overlapping entries and whether each
+// entry is contained in another entry. Of two entries covering the same
+// prefix, only the second one is considered redundant.
+//
+// Two prefixes either do not overlap or one contains the other, so a
+// single sweep over the entries sorted by address and prefix length keeps
+// the chain of prefixes containing the current entry on a stack.
+func findOverlaps(entries []listEntry) ([]entryOverlap, []bool) {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return comparePrefixes(entries[order[i]].prefix.Masked(), entries[order[j]].prefix.Masked()) < 0
+	})
+
+	overlaps := []entryOverlap{}
+	redundant := make([]bool, len(entries))
+	var stack []int
+	for _, i := range order {
+		p := entries[i].prefix.Masked()
+		for len(stack) > 0 && !entries[stack[len(stack)-1]].prefix.Masked().Overlaps(p) {
+			stack = stack[:len(stack)-1]
+		}
+		for _, c := range stack {
+			overlaps = append(overlaps, entryOverlap{Covering: entries[c].String(), Covered: entries[i].String()})
+		}
+		redundant[i] = len(stack) > 0
+		stack = append(stack, i)
+	}
+	return overlaps, redundant
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	tests := map[string]struct {
+		in            []string
+		wantOverlaps  []entryOverlap
+		wantRedundant []string
+	}{
+		"disjoint": {
+			in:            []string{"192.0.2.0/25", "192.0.2.128/25", "2001:db8::/32"},
+			wantOverlaps:  []entryOverlap{},
+			wantRedundant: []string{},
+		},
+		"nested": {
+			in: []string{"192.0.2.1", "192.0.2.0/24", "192.0.2.0/25", "198.51.100.1"},
+			wantOverlaps: []entryOverlap{
+				{Covering: "192.0.2.0/24", Covered: "192.0.2.0/25"},
+				{Covering: "192.0.2.0/24", Covered: "192.0.2.1"},
+				{Covering: "192.0.2.0/25", Covered: "192.0.2.1"},
+			},
+			wantRedundant: []string{"192.0.2.1", "192.0.2.0/25"},
+		},
+		"same prefix": {
+			in: []string{"192.0.2.1", "192.0.2.1/32", "192.0.2.5/24", "192.0.2.0/24"},
+			wantOverlaps: []entryOverlap{
+				{Covering: "192.0.2.5/24", Covered: "192.0.2.0/24"},
+				{Covering: "192.0.2.5/24", Covered: "192.0.2.1"},
+				{Covering: "192.0.2.0/24", Covered: "192.0.2.1"},
+				{Covering: "192.0.2.5/24", Covered: "192.0.2.1/32"},
+				{Covering: "192.0.2.0/24", Covered: "192.0.2.1/32"},
+				{Covering: "192.0.2.1", Covered: "192.0.2.1/32"},
+			},
+			wantRedundant: []string{"192.0.2.1", "192.0.2.1/32", "192.0.2.0/24"},
+		},
+		"stack is unwound": {
+			in: []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.1.0/24", "10.2.0.0/16", "11.0.0.0/8"},
+			wantOverlaps: []entryOverlap{
+				{Covering: "10.0.0.0/8", Covered: "10.1.0.0/16"},
+				{Covering: "10.0.0.0/8", Covered: "10.1.1.0/24"},
+				{Covering: "10.1.0.0/16", Covered: "10.1.1.0/24"},
+				{Covering: "10.0.0.0/8", Covered: "10.2.0.0/16"},
+			},
+			wantRedundant: []string{"10.1.0.0/16", "10.1.1.0/24", "10.2.0.0/16"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			overlaps, redundant := findOverlaps(entries)
+			if !reflect.DeepEqual(overlaps, tc.wantOverlaps) {
+				t.Errorf("got overlaps %v, want %v", overlaps, tc.wantOverlaps)
+			}
+			haveRedundant := []string{}
+			for i, r := range redundant {
+				if r {
+					haveRedundant = append(haveRedundant, entries[i].String())
+				}
+			}
+			if !reflect.DeepEqual(haveRedundant, tc.wantRedundant) {
+				t.Errorf("got redundant %v, want %v", haveRedundant, tc.wantRedundant)
+			}
+		})
+	}
+}
+
+func TestFindOverlapsLarge(t *testing.T) {
+	// A covering prefix followed by many disjoint entries must produce one
+	// overlap per entry.
+	list := []string{"10.0.0.0/8"}
+	for i := 0; i < 50000; i++ {
+		list = append(list, fmt.Sprintf("10.%d.%d.%d/32", i>>16&0xff, i>>8&0xff, i&0xff))
+	}
+	entries, err := parseEntries(list)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	overlaps, _ := findOverlaps(entries)
+	if len(overlaps) != 50000 {
+		t.Errorf("got %d overlaps, want 50000", len(overlaps))
+	}
+}
@@ -151,7 +151,7 @@ func (p *NBListsProvider) Resources(ctx context.Context) []func() resource.Resou
 }
 
 func (p *NBListsProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{NewListDataSource}
+	return []func() datasource.DataSource{NewListDataSource, NewSetDataSource}
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"net/netip"
+	"sort"
+)
+
+// renderOptions are the options passed to renderers.
+type renderOptions struct {
+	// name is the name of the set, chain or list to render.
+	name string
+
+	// ge4, le4, ge6 and le6 are the ge and le of prefix-list entries.
+	// 0 means not set.
+	ge4 int
+	le4 int
+	ge6 int
+	le6 int
+
+	// denyAll appends a rule denying everything else.
+	denyAll bool
+
+	// rpsl are the attributes of objects rendered by the rpsl format.
+	rpsl rpslOptions
+}
+
+// renderer renders entries in a configuration format.
+type renderer func(entries []listEntry, opts renderOptions) (string, error)
+
+// renderers are the formats supported by the format attribute.
+var renderers = map[string]renderer{}
+
+// renderFormats returns the names of all renderers, sorted.
+func renderFormats() []string {
+	ret := make([]string, 0, len(renderers))
+	for f := range renderers {
+		ret = append(ret, f)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// splitFamilies returns the aggregated IPv4 and IPv6 prefixes of entries.
+func splitFamilies(entries []listEntry) ([]netip.Prefix, []netip.Prefix) {
+	var prefixes4, prefixes6 []netip.Prefix
+	for _, e := range aggregateEntries(entries) {
+		if e.Addr().Is4() {
+			prefixes4 = append(prefixes4, e)
+		} else {
+			prefixes6 = append(prefixes6, e)
+		}
+	}
+	return prefixes4, prefixes6
+}
+
+// uniqueFamilies returns the masked IPv4 and IPv6 prefixes of entries,
+// each sorted and without duplicates but not aggregated.
+func uniqueFamilies(entries []listEntry) ([]netip.Prefix, []netip.Prefix) {
+	var prefixes4, prefixes6 []netip.Prefix
+	for _, e := range entries {
+		if e.prefix.Addr().Is4() {
+			prefixes4 = append(prefixes4, e.prefix.Masked())
+		} else {
+			prefixes6 = append(prefixes6, e.prefix.Masked())
+		}
+	}
+	return uniquePrefixes(prefixes4), uniquePrefixes(prefixes6)
+}
+
+// aggregateEntries returns the aggregated prefixes of entries.
+func aggregateEntries(entries []listEntry) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		prefixes = append(prefixes, e.prefix)
+	}
+	return aggregatePrefixes(prefixes)
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+const (
+	formatNftablesSet   = "nftables_set"
+	formatIPSet         = "ipset"
+	formatIPTablesRules = "iptables_rules"
+	formatNetsh         = "netsh"
+)
+
+func init() {
+	renderers[formatNftablesSet] = renderNftablesSet
+	renderers[formatIPSet] = renderIPSet
+	renderers[formatIPTablesRules] = renderIPTablesRules
+	renderers[formatNetsh] = renderNetsh
+}
+
+// renderNftablesSet renders a named interval set per address family.
+// Entries are aggregated because elements of interval sets must not overlap.
+func renderNftablesSet(entries []listEntry, opts renderOptions) (string, error) {
+	prefixes4, prefixes6 := splitFamilies(entries)
+
+	var b strings.Builder
+	for _, f := range []struct {
+		suffix   string
+		typ      string
+		prefixes []netip.Prefix
+	}{
+		{"4", "ipv4_addr", prefixes4},
+		{"6", "ipv6_addr", prefixes6},
+	} {
+		fmt.Fprintf(&b, "set %s%s {\n", opts.name, f.suffix)
+		fmt.Fprintf(&b, "\ttype %s\n", f.typ)
+		b.WriteString("\tflags interval\n")
+		if len(f.prefixes) > 0 {
+			fmt.Fprintf(&b, "\telements = { %s }\n", strings.Join(prefixStrings(f.prefixes), ", "))
+		}
+		b.WriteString("}\n")
+	}
+	return b.String(), nil
+}
+
+// renderIPSet renders an ipset restore file with a hash:net set per address family.
+func renderIPSet(entries []listEntry, opts renderOptions) (string, error) {
+	prefixes4, prefixes6 := splitFamilies(entries)
+
+	var b strings.Builder
+	for _, f := range []struct {
+		suffix   string
+		family   string
+		prefixes []netip.Prefix
+	}{
+		{"4", "inet", prefixes4},
+		{"6", "inet6", prefixes6},
+	} {
+		name := opts.name + f.suffix
+		fmt.Fprintf(&b, "create %s hash:net family %s -exist\n", name, f.family)
+		fmt.Fprintf(&b, "flush %s\n", name)
+		for _, p := range f.prefixes {
+			// hash:net does not support a prefix length of 0.
+			if p.Bits() == 0 {
+				a := p.Addr()
+				fmt.Fprintf(&b, "add %s %s\n", name, netip.PrefixFrom(a, 1))
+				fmt.Fprintf(&b, "add %s %s\n", name, netip.PrefixFrom(flipBit(a, 0), 1))
+				continue
+			}
+			fmt.Fprintf(&b, "add %s %s\n", name, p)
+		}
+	}
+	return b.String(), nil
+}
+
+// renderIPTablesRules renders a shell script feeding iptables-restore and
+// ip6tables-restore a chain that accepts traffic from the entries.
+// With --noflush, only the declared chain is created or flushed, so the
+// script can be run repeatedly and each family is replaced atomically.
+func renderIPTablesRules(entries []listEntry, opts renderOptions) (string, error) {
+	prefixes4, prefixes6 := splitFamilies(entries)
+
+	var b strings.Builder
+	for _, f := range []struct {
+		cmd      string
+		prefixes []netip.Prefix
+	}{
+		{"iptables-restore", prefixes4},
+		{"ip6tables-restore", prefixes6},
+	} {
+		fmt.Fprintf(&b, "%s --noflush <<'EOF'\n", f.cmd)
+		b.WriteString("*filter\n")
+		fmt.Fprintf(&b, ":%s - [0:0]\n", opts.name)
+		for _, p := range f.prefixes {
+			fmt.Fprintf(&b, "-A %s -s %s -j ACCEPT\n", opts.name, p)
+		}
+		b.WriteString("COMMIT\n")
+		b.WriteString("EOF\n")
+	}
+	return b.String(), nil
+}
+
+// renderNetsh renders Windows Firewall rules allowing inbound traffic from
+// the entries, one rule per address family.
+// Each rule is deleted before it is added, so the script can be run
+// repeatedly and the rule of a family without entries is removed. netsh
+// reports an error for the delete if the rule doesn't exist yet.
+func renderNetsh(entries []listEntry, opts renderOptions) (string, error) {
+	prefixes4, prefixes6 := splitFamilies(entries)
+
+	var b strings.Builder
+	for _, f := range []struct {
+		family   string
+		prefixes []netip.Prefix
+	}{
+		{"IPv4", prefixes4},
+		{"IPv6", prefixes6},
+	} {
+		name := fmt.Sprintf("%s (%s)", opts.name, f.family)
+		fmt.Fprintf(&b, "netsh advfirewall firewall delete rule name=\"%s\"\n", name)
+		if len(f.prefixes) == 0 {
+			continue
+		}
+		fmt.Fprintf(
+			&b,
+			"netsh advfirewall firewall add rule name=\"%s\" dir=in action=allow remoteip=%s\n",
+			name, strings.Join(prefixStrings(f.prefixes), ","),
+		)
+	}
+	return b.String(), nil
+}
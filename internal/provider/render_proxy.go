@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	formatNginx     = "nginx"
+	formatHAProxy   = "haproxy"
+	formatApache    = "apache"
+	formatEnvoyRBAC = "envoy_rbac"
+)
+
+func init() {
+	renderers[formatNginx] = renderNginx
+	renderers[formatHAProxy] = renderHAProxy
+	renderers[formatApache] = renderApache
+	renderers[formatEnvoyRBAC] = renderEnvoyRBAC
+}
+
+// renderNginx renders ngx_http_access_module allow directives.
+func renderNginx(entries []listEntry, opts renderOptions) (string, error) {
+	var b strings.Builder
+	for _, p := range aggregateEntries(entries) {
+		fmt.Fprintf(&b, "allow %s;\n", p)
+	}
+	if opts.denyAll {
+		b.WriteString("deny all;\n")
+	}
+	return b.String(), nil
+}
+
+// renderHAProxy renders an ACL file with one pattern per line for use with
+// `acl <name> src -f <file>`.
+func renderHAProxy(entries []listEntry, opts renderOptions) (string, error) {
+	var b strings.Builder
+	for _, p := range aggregateEntries(entries) {
+		fmt.Fprintf(&b, "%s\n", p)
+	}
+	return b.String(), nil
+}
+
+// renderApache renders mod_authz_host Require ip directives.
+// Apache denies requests not matched by a Require directive.
+func renderApache(entries []listEntry, opts renderOptions) (string, error) {
+	var b strings.Builder
+	for _, p := range aggregateEntries(entries) {
+		fmt.Fprintf(&b, "Require ip %s\n", p)
+	}
+	return b.String(), nil
+}
+
+// envoyRBAC is the Envoy RBAC config rendered by renderEnvoyRBAC.
+type envoyRBAC struct {
+	Action   string                 `json:"action"`
+	Policies map[string]envoyPolicy `json:"policies"`
+}
+
+type envoyPolicy struct {
+	Permissions []envoyPermission `json:"permissions"`
+	Principals  []envoyPrincipal  `json:"principals"`
+}
+
+type envoyPermission struct {
+	Any bool `json:"any"`
+}
+
+type envoyPrincipal struct {
+	RemoteIP envoyCIDRRange `json:"remote_ip"`
+}
+
+type envoyCIDRRange struct {
+	AddressPrefix string `json:"address_prefix"`
+	PrefixLen     int    `json:"prefix_len"`
+}
+
+// renderEnvoyRBAC renders an Envoy RBAC config as JSON with an ALLOW policy
+// matching the entries. The policy is omitted if there are no entries since
+// Envoy requires at least one principal; an ALLOW config without policies
+// denies all requests.
+func renderEnvoyRBAC(entries []listEntry, opts renderOptions) (string, error) {
+	rbac := envoyRBAC{Action: "ALLOW", Policies: map[string]envoyPolicy{}}
+
+	prefixes := aggregateEntries(entries)
+	if len(prefixes) > 0 {
+		policy := envoyPolicy{Permissions: []envoyPermission{{Any: true}}}
+		for _, p := range prefixes {
+			policy.Principals = append(policy.Principals, envoyPrincipal{
+				RemoteIP: envoyCIDRRange{AddressPrefix: p.Addr().String(), PrefixLen: p.Bits()},
+			})
+		}
+		rbac.Policies[opts.name] = policy
+	}
+
+	b, err := json.MarshalIndent(rbac, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestRenderDenyAll(t *testing.T) {
+	entries, err := parseEntries([]string{"192.0.2.0/25", "192.0.2.128/25", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	tests := map[string]string{
+		formatNginx:   "allow 192.0.2.0/24;\nallow 2001:db8::1/128;\ndeny all;\n",
+		formatHAProxy: "192.0.2.0/24\n2001:db8::1/128\n",
+		formatApache:  "Require ip 192.0.2.0/24\nRequire ip 2001:db8::1/128\n",
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			have, err := renderers[format](entries, renderOptions{name: "allow", denyAll: true})
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if have != want {
+				t.Errorf("expected %q but got %q", want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package provider
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+const (
+	formatCiscoIOS  = "cisco_ios"
+	formatCiscoNXOS = "cisco_nxos"
+	formatAristaEOS = "arista_eos"
+	formatJunos     = "junos"
+	formatBIRD      = "bird"
+	formatFRR       = "frr"
+)
+
+const (
+	// maxSeqIOS is the largest prefix-list sequence number on IOS, NX-OS and FRR.
+	maxSeqIOS = 4294967294
+	// maxSeqEOS is the largest prefix-list sequence number on EOS.
+	maxSeqEOS = 65535
+)
+
+func init() {
+	renderers[formatCiscoIOS] = renderIOSPrefixList
+	renderers[formatCiscoNXOS] = renderIOSPrefixList
+	renderers[formatFRR] = renderIOSPrefixList
+	renderers[formatAristaEOS] = renderEOSPrefixList
+	renderers[formatJunos] = renderJunosPrefixList
+	renderers[formatBIRD] = renderBIRDSet
+}
+
+// prefixListEntry is an entry of a router prefix-list.
+type prefixListEntry struct {
+	prefix netip.Prefix
+	seq    uint64
+	// ge and le are 0 if they do not apply to the entry.
+	ge int
+	le int
+}
+
+// String returns the entry in IOS syntax without the sequence number.
+func (e prefixListEntry) String() string {
+	s := "permit " + e.prefix.String()
+	if e.ge > 0 {
+		s += fmt.Sprintf(" ge %d", e.ge)
+	}
+	if e.le > 0 {
+		s += fmt.Sprintf(" le %d", e.le)
+	}
+	return s
+}
+
+// minLen returns the shortest prefix length matched by the entry.
+func (e prefixListEntry) minLen() int {
+	if e.ge > 0 {
+		return e.ge
+	}
+	return e.prefix.Bits()
+}
+
+// maxLen returns the longest prefix length matched by the entry.
+func (e prefixListEntry) maxLen() int {
+	switch {
+	case e.le > 0:
+		return e.le
+	case e.ge > 0:
+		return e.prefix.Addr().BitLen()
+	}
+	return e.prefix.Bits()
+}
+
+// prefixListFamily is the IPv4 or IPv6 part of a prefix-list.
+type prefixListFamily struct {
+	is4     bool
+	entries []prefixListEntry
+}
+
+// prefixLists returns the IPv4 and IPv6 prefix-list entries of entries
+// sorted by prefix. Sequence numbers are assigned by numberPrefixList.
+//
+// Entries are not aggregated since a prefix-list matches exact prefixes
+// unless ge or le is set.
+func prefixLists(entries []listEntry, opts renderOptions) []prefixListFamily {
+	prefixes4, prefixes6 := uniqueFamilies(entries)
+
+	families := []prefixListFamily{{is4: true}, {is4: false}}
+	for i, f := range []struct {
+		prefixes []netip.Prefix
+		ge       int
+		le       int
+	}{
+		{prefixes4, opts.ge4, opts.le4},
+		{prefixes6, opts.ge6, opts.le6},
+	} {
+		for _, p := range f.prefixes {
+			e := prefixListEntry{prefix: p}
+			if f.ge > p.Bits() {
+				e.ge = f.ge
+			}
+			if f.le > p.Bits() && f.le >= e.ge {
+				e.le = f.le
+			}
+			families[i].entries = append(families[i].entries, e)
+		}
+	}
+	return families
+}
+
+// numberPrefixList assigns sequence numbers between 1 and maxSeq to the
+// entries of f and sorts them by sequence number, as devices show them.
+func numberPrefixList(f *prefixListFamily, maxSeq uint64) error {
+	prefixes := make([]netip.Prefix, 0, len(f.entries))
+	for _, e := range f.entries {
+		prefixes = append(prefixes, e.prefix)
+	}
+	seqs, err := prefixSeqs(prefixes, maxSeq)
+	if err != nil {
+		return err
+	}
+	for i := range f.entries {
+		f.entries[i].seq = seqs[i]
+	}
+	sort.Slice(f.entries, func(i, j int) bool {
+		return f.entries[i].seq < f.entries[j].seq
+	})
+	return nil
+}
+
+// uniquePrefixes returns prefixes sorted by address and prefix length
+// without duplicates.
+func uniquePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sortPrefixes(prefixes)
+	ret := make([]netip.Prefix, 0, len(prefixes))
+	for i, p := range prefixes {
+		if i == 0 || p != prefixes[i-1] {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// seqSlotSize is the number of sequence numbers reserved for each prefix.
+// Prefixes use the middle of their slot (5, 15, 25, ...) so entries can be
+// added by hand before or after them.
+const seqSlotSize = 10
+
+// prefixSeqs returns a sequence number between 1 and maxSeq for each prefix.
+//
+// The number is derived from a hash of the prefix alone rather than from
+// its position in the list, so adding or removing an entry does not
+// renumber the other entries. Prefixes hashing to the same slot take the
+// next free slot in order of prefix; only those prefixes can be renumbered
+// by a change, which is rare unless the list fills a large part of the
+// sequence space.
+func prefixSeqs(prefixes []netip.Prefix, maxSeq uint64) ([]uint64, error) {
+	slots := (maxSeq - seqSlotSize/2) / seqSlotSize
+	if uint64(len(prefixes)) > slots {
+		return nil, fmt.Errorf("%d entries do not fit in the sequence numbers up to %d", len(prefixes), maxSeq)
+	}
+
+	home := make([]uint64, len(prefixes))
+	order := make([]int, len(prefixes))
+	for i, p := range prefixes {
+		h := fnv.New64a()
+		b, _ := p.MarshalBinary()
+		_, _ = h.Write(b)
+		home[i] = h.Sum64() % slots
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if home[a] != home[b] {
+			return home[a] < home[b]
+		}
+		return comparePrefixes(prefixes[a], prefixes[b]) < 0
+	})
+
+	ret := make([]uint64, len(prefixes))
+	used := make(map[uint64]bool, len(prefixes))
+	for _, i := range order {
+		slot := home[i]
+		for used[slot] {
+			slot = (slot + 1) % slots
+		}
+		used[slot] = true
+		ret[i] = slot*seqSlotSize + seqSlotSize/2
+	}
+	return ret, nil
+}
+
+// renderIOSPrefixList renders an ip prefix-list and an ipv6 prefix-list
+// in IOS syntax which is shared by NX-OS and FRR. Entries are rendered in
+// sequence order, as devices show them.
+func renderIOSPrefixList(entries []listEntry, opts renderOptions) (string, error) {
+	var b strings.Builder
+	for _, f := range prefixLists(entries, opts) {
+		if err := numberPrefixList(&f, maxSeqIOS); err != nil {
+			return "", err
+		}
+		cmd := "ipv6 prefix-list"
+		if f.is4 {
+			cmd = "ip prefix-list"
+		}
+		for _, e := range f.entries {
+			fmt.Fprintf(&b, "%s %s seq %d %s\n", cmd, opts.name, e.seq, e)
+		}
+	}
+	return b.String(), nil
+}
+
+// renderEOSPrefixList renders an ip prefix-list and an ipv6 prefix-list
+// in EOS syntax.
+func renderEOSPrefixList(entries []listEntry, opts renderOptions) (string, error) {
+	var b strings.Builder
+	for _, f := range prefixLists(entries, opts) {
+		if len(f.entries) == 0 {
+			continue
+		}
+		if err := numberPrefixList(&f, maxSeqEOS); err != nil {
+			return "", err
+		}
+		cmd := "ipv6 prefix-list"
+		if f.is4 {
+			cmd = "ip prefix-list"
+		}
+		fmt.Fprintf(&b, "%s %s\n", cmd, opts.name)
+		for _, e := range f.entries {
+			fmt.Fprintf(&b, "   seq %d %s\n", e.seq, e)
+		}
+	}
+	return b.String(), nil
+}
+
+// renderJunosPrefixList renders a policy-options prefix-list containing
+// both address families. Junos prefix-lists only match exact prefixes, so
+// a route-filter-list is rendered instead if ge or le apply to an entry.
+func renderJunosPrefixList(entries []listEntry, opts renderOptions) (string, error) {
+	var all []prefixListEntry
+	routeFilter := false
+	for _, f := range prefixLists(entries, opts) {
+		for _, e := range f.entries {
+			all = append(all, e)
+			routeFilter = routeFilter || e.ge > 0 || e.le > 0
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("policy-options {\n")
+	if routeFilter {
+		fmt.Fprintf(&b, "    route-filter-list %s {\n", opts.name)
+	} else {
+		fmt.Fprintf(&b, "    prefix-list %s {\n", opts.name)
+	}
+	for _, e := range all {
+		switch {
+		case !routeFilter:
+			fmt.Fprintf(&b, "        %s;\n", e.prefix)
+		case e.ge > 0:
+			fmt.Fprintf(&b, "        %s prefix-length-range /%d-/%d;\n", e.prefix, e.minLen(), e.maxLen())
+		case e.le > 0:
+			fmt.Fprintf(&b, "        %s upto /%d;\n", e.prefix, e.le)
+		default:
+			fmt.Fprintf(&b, "        %s exact;\n", e.prefix)
+		}
+	}
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// renderBIRDSet renders a BIRD prefix set constant per address family.
+// A family without entries is omitted since BIRD does not accept empty sets.
+func renderBIRDSet(entries []listEntry, opts renderOptions) (string, error) {
+	var b strings.Builder
+	for _, f := range prefixLists(entries, opts) {
+		if len(f.entries) == 0 {
+			continue
+		}
+		suffix := "6"
+		if f.is4 {
+			suffix = "4"
+		}
+		elems := make([]string, 0, len(f.entries))
+		for _, e := range f.entries {
+			switch {
+			case e.ge > 0 || e.le > 0:
+				elems = append(elems, fmt.Sprintf("%s{%d,%d}", e.prefix, e.minLen(), e.maxLen()))
+			default:
+				elems = append(elems, e.prefix.String())
+			}
+		}
+		fmt.Fprintf(&b, "define %s%s = [ %s ];\n", opts.name, suffix, strings.Join(elems, ", "))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrefixListGeLe(t *testing.T) {
+	entries, err := parseEntries([]string{
+		"192.0.2.0/24",
+		"198.51.100.0/26",
+		"203.0.113.7",
+		"2001:db8::/32",
+		"2001:db8:1::/64",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	opts := renderOptions{name: "allow", ge4: 25, le4: 28, le6: 48}
+
+	for _, format := range []string{formatCiscoIOS, formatCiscoNXOS, formatFRR, formatAristaEOS, formatJunos, formatBIRD} {
+		t.Run(format, func(t *testing.T) {
+			have, err := renderers[format](entries, opts)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			checkGolden(t, filepath.Join("testdata", "render", format+"_ge_le.golden"), have)
+		})
+	}
+}
+
+func TestPrefixSeqs(t *testing.T) {
+	var prefixes []netip.Prefix
+	for i := 0; i < 256; i++ {
+		prefixes = append(prefixes, netip.PrefixFrom(netip.AddrFrom4([4]byte{192, 0, 2, byte(i)}), 32))
+	}
+
+	for _, maxSeq := range []uint64{maxSeqIOS, maxSeqEOS} {
+		seqs, err := prefixSeqs(prefixes, maxSeq)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+		seen := map[uint64]bool{}
+		for i, seq := range seqs {
+			if seq < 1 || seq > maxSeq {
+				t.Errorf("sequence number %d of %s is out of range", seq, prefixes[i])
+			}
+			// Every entry leaves a gap on both sides for manual entries.
+			if seq%seqSlotSize != seqSlotSize/2 {
+				t.Errorf("sequence number %d of %s is not in the middle of its slot", seq, prefixes[i])
+			}
+			if seen[seq] {
+				t.Errorf("sequence number %d is used twice", seq)
+			}
+			seen[seq] = true
+		}
+	}
+
+	if _, err := prefixSeqs(prefixes, 100); err == nil {
+		t.Errorf("expected an error when the entries do not fit")
+	}
+}
+
+func TestPrefixSeqsStable(t *testing.T) {
+	tests := map[string]struct {
+		prefixes []netip.Prefix
+		inserted netip.Prefix
+		maxSeq   uint64
+	}{
+		"ipv6 /32": {
+			prefixes: func() []netip.Prefix {
+				var ret []netip.Prefix
+				for i := 1; i < 200; i += 2 {
+					ret = append(ret, netip.MustParsePrefix(fmt.Sprintf("2001:db8:0:%x::/64", i)))
+				}
+				return ret
+			}(),
+			inserted: netip.MustParsePrefix("2001:db8::/64"),
+			maxSeq:   maxSeqIOS,
+		},
+		"eos /16": {
+			prefixes: func() []netip.Prefix {
+				var ret []netip.Prefix
+				for i := 1; i < 100; i++ {
+					ret = append(ret, netip.MustParsePrefix(fmt.Sprintf("10.20.%d.0/24", i)))
+				}
+				return ret
+			}(),
+			inserted: netip.MustParsePrefix("10.20.0.0/24"),
+			maxSeq:   maxSeqEOS,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			before, err := prefixSeqs(tc.prefixes, tc.maxSeq)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			after, err := prefixSeqs(append([]netip.Prefix{tc.inserted}, tc.prefixes...), tc.maxSeq)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			for i, p := range tc.prefixes {
+				if before[i] != after[i+1] {
+					t.Errorf("inserting %s changed the sequence number of %s from %d to %d", tc.inserted, p, before[i], after[i+1])
+				}
+			}
+		})
+	}
+}
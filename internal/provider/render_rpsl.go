@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+const formatRPSL = "rpsl"
+
+var (
+	rpslASNRegexp      = regexp.MustCompile(`^AS[0-9]+$`)
+	rpslObjectRegexp   = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*[A-Za-z0-9]$`)
+	rpslSourceRegexp   = regexp.MustCompile(`^[A-Z][A-Z0-9-]*$`)
+	rpslRouteSetRegexp = regexp.MustCompile(`^((AS[0-9]+|RS-[A-Za-z0-9_-]+):)*RS-[A-Za-z0-9_-]+$`)
+)
+
+func init() {
+	renderers[formatRPSL] = renderRPSL
+}
+
+// rpslOptions are the attributes of rendered RPSL objects.
+type rpslOptions struct {
+	origin   string
+	mntBy    []string
+	source   string
+	routeSet string
+}
+
+// rpslWriter writes RPSL objects.
+type rpslWriter struct {
+	b strings.Builder
+}
+
+// attr writes an attribute with its value aligned like the RIPE database.
+func (w *rpslWriter) attr(name string, value string) {
+	fmt.Fprintf(&w.b, "%-16s%s\n", name+":", value)
+}
+
+// end ends the current object.
+func (w *rpslWriter) end(opts rpslOptions) {
+	for _, m := range opts.mntBy {
+		w.attr("mnt-by", m)
+	}
+	w.attr("source", opts.source)
+	w.b.WriteString("\n")
+}
+
+// renderRPSL renders a route or route6 object per entry and a route-set
+// with the entries as members if a route-set name is set.
+// Entries are not aggregated since route objects must match the announced
+// prefixes exactly.
+func renderRPSL(entries []listEntry, opts renderOptions) (string, error) {
+	prefixes4, prefixes6 := uniqueFamilies(entries)
+	families := []struct {
+		class    string
+		members  string
+		prefixes []netip.Prefix
+	}{
+		{"route", "members", prefixes4},
+		{"route6", "mp-members", prefixes6},
+	}
+
+	var w rpslWriter
+	for _, f := range families {
+		for _, p := range f.prefixes {
+			w.attr(f.class, p.String())
+			w.attr("origin", opts.rpsl.origin)
+			w.end(opts.rpsl)
+		}
+	}
+
+	if opts.rpsl.routeSet != "" {
+		w.attr("route-set", opts.rpsl.routeSet)
+		for _, f := range families {
+			for _, p := range f.prefixes {
+				w.attr(f.members, p.String())
+			}
+		}
+		w.end(opts.rpsl)
+	}
+	return strings.TrimSuffix(w.b.String(), "\n"), nil
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// rpslAttrRegexp matches an RPSL attribute line.
+var rpslAttrRegexp = regexp.MustCompile(`^([a-z][a-z0-9-]*[a-z0-9]):( *)(.*)$`)
+
+// rpslClasses are the mandatory and single-valued attributes of the
+// classes rendered by the rpsl format.
+var rpslClasses = map[string]struct {
+	mandatory []string
+	single    []string
+}{
+	"route":     {mandatory: []string{"origin", "mnt-by", "source"}, single: []string{"origin", "source"}},
+	"route6":    {mandatory: []string{"origin", "mnt-by", "source"}, single: []string{"origin", "source"}},
+	"route-set": {mandatory: []string{"mnt-by", "source"}, single: []string{"source"}},
+}
+
+// validateRPSL checks that text is a sequence of valid RPSL route, route6
+// and route-set objects separated by blank lines.
+func validateRPSL(t *testing.T, text string) {
+	t.Helper()
+	if text == "" {
+		return
+	}
+	if strings.HasSuffix(text, "\n\n") || !strings.HasSuffix(text, "\n") {
+		t.Errorf("expected objects to end with a single newline")
+	}
+
+	for _, obj := range strings.Split(strings.TrimSuffix(text, "\n"), "\n\n") {
+		attrs := map[string][]string{}
+		var class string
+		for i, line := range strings.Split(obj, "\n") {
+			m := rpslAttrRegexp.FindStringSubmatch(line)
+			if m == nil {
+				t.Errorf("invalid attribute line %q", line)
+				continue
+			}
+			if m[3] == "" || strings.TrimSpace(m[3]) != m[3] {
+				t.Errorf("invalid value in %q", line)
+			}
+			if i == 0 {
+				class = m[1]
+			}
+			attrs[m[1]] = append(attrs[m[1]], m[3])
+		}
+
+		c, ok := rpslClasses[class]
+		if !ok {
+			t.Errorf("unexpected class %q", class)
+			continue
+		}
+		if len(attrs[class]) != 1 {
+			t.Errorf("expected a single %s attribute but got %v", class, attrs[class])
+		}
+		for _, a := range c.mandatory {
+			if len(attrs[a]) == 0 {
+				t.Errorf("%s object %v is missing %s", class, attrs[class], a)
+			}
+		}
+		for _, a := range c.single {
+			if len(attrs[a]) > 1 {
+				t.Errorf("%s object %v has multiple %s", class, attrs[class], a)
+			}
+		}
+		for _, v := range attrs["origin"] {
+			if !rpslASNRegexp.MatchString(v) {
+				t.Errorf("invalid origin %q", v)
+			}
+		}
+		for _, v := range attrs["mnt-by"] {
+			if !rpslObjectRegexp.MatchString(v) {
+				t.Errorf("invalid mnt-by %q", v)
+			}
+		}
+
+		switch class {
+		case "route", "route6":
+			p, err := netip.ParsePrefix(attrs[class][0])
+			if err != nil || p != p.Masked() || p.Addr().Is4() != (class == "route") {
+				t.Errorf("invalid %s prefix %q", class, attrs[class][0])
+			}
+		case "route-set":
+			if !rpslRouteSetRegexp.MatchString(attrs[class][0]) {
+				t.Errorf("invalid route-set name %q", attrs[class][0])
+			}
+			for _, a := range []string{"members", "mp-members"} {
+				for _, v := range attrs[a] {
+					p, err := netip.ParsePrefix(v)
+					if err != nil || p.Addr().Is4() != (a == "members") {
+						t.Errorf("invalid %s %q", a, v)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRPSLSyntax(t *testing.T) {
+	goldens, err := filepath.Glob(filepath.Join("testdata", "render", formatRPSL+"_*.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goldens) == 0 {
+		t.Fatal("expected rpsl golden files")
+	}
+	for _, g := range goldens {
+		t.Run(filepath.Base(g), func(t *testing.T) {
+			b, err := os.ReadFile(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			validateRPSL(t, string(b))
+		})
+	}
+}
+
+func TestRenderRPSLWithoutRouteSet(t *testing.T) {
+	entries, err := parseEntries([]string{"192.0.2.5/24", "2001:db8::/32"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	have, err := renderRPSL(entries, renderOptions{rpsl: rpslOptions{
+		origin: "AS64500",
+		mntBy:  []string{"MAINT-A", "MAINT-B"},
+		source: "RIPE",
+	}})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	want := "route:          192.0.2.0/24\n" +
+		"origin:         AS64500\n" +
+		"mnt-by:         MAINT-A\n" +
+		"mnt-by:         MAINT-B\n" +
+		"source:         RIPE\n" +
+		"\n" +
+		"route6:         2001:db8::/32\n" +
+		"origin:         AS64500\n" +
+		"mnt-by:         MAINT-A\n" +
+		"mnt-by:         MAINT-B\n" +
+		"source:         RIPE\n"
+	if have != want {
+		t.Errorf("expected %q but got %q", want, have)
+	}
+	validateRPSL(t, have)
+}
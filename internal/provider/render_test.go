@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"flag"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// renderTestCases are the lists every renderer is tested with.
+var renderTestCases = map[string][]string{
+	"mixed": {
+		"192.0.2.0/25",
+		"192.0.2.128/25",
+		"192.0.2.5",
+		"198.51.100.7/32",
+		"2001:db8::/48",
+		"2001:db8:1::1",
+	},
+	"empty":         {},
+	"default_route": {"0.0.0.0/0", "::/0"},
+}
+
+// renderTestOptions are the options every renderer is tested with.
+var renderTestOptions = renderOptions{
+	name: "allow",
+	rpsl: rpslOptions{
+		origin:   "AS64500",
+		mntBy:    []string{"MAINT-EXAMPLE"},
+		source:   "TEST",
+		routeSet: "AS64500:RS-ALLOW",
+	},
+}
+
+func TestRenderers(t *testing.T) {
+	for _, format := range renderFormats() {
+		for name, list := range renderTestCases {
+			t.Run(format+"/"+name, func(t *testing.T) {
+				entries, err := parseEntries(list)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				have, err := renderers[format](entries, renderTestOptions)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				checkGolden(t, filepath.Join("testdata", "render", format+"_"+name+".golden"), have)
+			})
+		}
+	}
+}
+
+func TestUniqueFamilies(t *testing.T) {
+	entries, err := parseEntries([]string{"198.51.100.0/24", "2001:db8::1/64", "192.0.2.1/24", "192.0.2.0/24", "2001:db8::/64"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	have4, have6 := uniqueFamilies(entries)
+	want4 := []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("198.51.100.0/24")}
+	want6 := []netip.Prefix{netip.MustParsePrefix("2001:db8::/64")}
+	if !reflect.DeepEqual(have4, want4) {
+		t.Errorf("expected IPv4 %v but got %v", want4, have4)
+	}
+	if !reflect.DeepEqual(have6, want6) {
+		t.Errorf("expected IPv6 %v but got %v", want6, have6)
+	}
+}
+
+// checkGolden compares have to the golden file, updating it first if -update is set.
+func checkGolden(t *testing.T, golden string, have string) {
+	t.Helper()
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(have), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("error reading golden file (run with -update to create it): %v", err)
+	}
+	if have != string(want) {
+		t.Errorf("output does not match the golden file %s:\n%s", golden, have)
+	}
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ptrName returns the reverse DNS name of a.
+func ptrName(a netip.Addr) string {
+	return reverseName(netip.PrefixFrom(a, a.BitLen()))
+}
+
+// reverseName returns the in-addr.arpa or ip6.arpa name of p.
+// The prefix length of p must be a multiple of 8 for IPv4 and 4 for IPv6.
+func reverseName(p netip.Prefix) string {
+	b := p.Addr().AsSlice()
+	var labels []string
+	if p.Addr().Is4() {
+		for i := p.Bits()/8 - 1; i >= 0; i-- {
+			labels = append(labels, fmt.Sprint(b[i]))
+		}
+		labels = append(labels, "in-addr", "arpa")
+	} else {
+		for i := p.Bits()/4 - 1; i >= 0; i-- {
+			labels = append(labels, fmt.Sprintf("%x", b[i/2]>>(4*(1-i%2))&0xf))
+		}
+		labels = append(labels, "ip6", "arpa")
+	}
+	return strings.Join(labels, ".")
+}
+
+// reverseZones returns the reverse DNS zones of p.
+//
+// Prefixes are split into the zones at the next octet (IPv4) or nibble
+// (IPv6) boundary. IPv4 prefixes longer than /24 are named as in
+// RFC 2317, e.g. 64/26.2.0.192.in-addr.arpa.
+func reverseZones(p netip.Prefix) []string {
+	p = p.Masked()
+	if p.Addr().Is4() && p.Bits() > 24 {
+		b := p.Addr().As4()
+		return []string{fmt.Sprintf("%d/%d.%s", b[3], p.Bits(), reverseName(netip.PrefixFrom(p.Addr(), 24).Masked()))}
+	}
+
+	step := 4
+	if p.Addr().Is4() {
+		step = 8
+	}
+	bits := (p.Bits() + step - 1) / step * step
+
+	var ret []string
+	for _, z := range splitPrefix(p, bits) {
+		ret = append(ret, reverseName(z))
+	}
+	return ret
+}
+
+// splitPrefix returns the subnets of p with the prefix length bits.
+func splitPrefix(p netip.Prefix, bits int) []netip.Prefix {
+	if p.Bits() >= bits {
+		return []netip.Prefix{p}
+	}
+	a := p.Addr()
+	return append(
+		splitPrefix(netip.PrefixFrom(a, p.Bits()+1), bits),
+		splitPrefix(netip.PrefixFrom(flipBit(a, p.Bits()), p.Bits()+1), bits)...,
+	)
+}
+
+// reverseDNS returns the PTR names of the single IPs in entries keyed by
+// address and the sorted reverse zones of the other entries.
+func reverseDNS(entries []listEntry) (map[string]string, []string) {
+	ptrNames := make(map[string]string)
+	var prefixes []netip.Prefix
+	for _, e := range entries {
+		if e.prefix.IsSingleIP() {
+			ptrNames[e.prefix.Addr().String()] = ptrName(e.prefix.Addr())
+			continue
+		}
+		prefixes = append(prefixes, e.prefix.Masked())
+	}
+
+	zones := []string{}
+	seen := make(map[string]bool)
+	for _, p := range uniquePrefixes(prefixes) {
+		for _, z := range reverseZones(p) {
+			if !seen[z] {
+				seen[z] = true
+				zones = append(zones, z)
+			}
+		}
+	}
+	return ptrNames, zones
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestPTRName(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.5":   "5.2.0.192.in-addr.arpa",
+		"2001:db8::1": "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	}
+	for addr, want := range tests {
+		t.Run(addr, func(t *testing.T) {
+			if have := ptrName(netip.MustParseAddr(addr)); have != want {
+				t.Errorf("expected %q but got %q", want, have)
+			}
+		})
+	}
+}
+
+func TestReverseZones(t *testing.T) {
+	tests := map[string][]string{
+		"0.0.0.0/0":    {"in-addr.arpa"},
+		"10.0.0.0/8":   {"10.in-addr.arpa"},
+		"192.0.2.0/24": {"2.0.192.in-addr.arpa"},
+		"10.20.0.0/22": {
+			"0.20.10.in-addr.arpa",
+			"1.20.10.in-addr.arpa",
+			"2.20.10.in-addr.arpa",
+			"3.20.10.in-addr.arpa",
+		},
+		"172.16.0.0/15": {"16.172.in-addr.arpa", "17.172.in-addr.arpa"},
+		"192.0.2.64/26": {"64/26.2.0.192.in-addr.arpa"},
+		"192.0.2.9/25":  {"0/25.2.0.192.in-addr.arpa"},
+		"2001:db8::/32": {"8.b.d.0.1.0.0.2.ip6.arpa"},
+		"2001:db8::/47": {"0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", "1.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"},
+		"::/0":          {"ip6.arpa"},
+	}
+	for prefix, want := range tests {
+		t.Run(prefix, func(t *testing.T) {
+			have := reverseZones(netip.MustParsePrefix(prefix))
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("expected %v but got %v", want, have)
+			}
+		})
+	}
+}
+
+func TestReverseDNS(t *testing.T) {
+	entries, err := parseEntries([]string{
+		"192.0.2.0/24",
+		"192.0.2.0/25",
+		"192.0.2.5",
+		"10.0.0.0/8",
+		"10.1.0.0/16",
+		"2001:db8::1",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ptrNames, zones := reverseDNS(entries)
+	wantPTR := map[string]string{
+		"192.0.2.5":   "5.2.0.192.in-addr.arpa",
+		"2001:db8::1": "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	}
+	if !reflect.DeepEqual(ptrNames, wantPTR) {
+		t.Errorf("expected %v but got %v", wantPTR, ptrNames)
+	}
+	wantZones := []string{
+		"10.in-addr.arpa",
+		"1.10.in-addr.arpa",
+		"2.0.192.in-addr.arpa",
+		"0/25.2.0.192.in-addr.arpa",
+	}
+	if !reflect.DeepEqual(zones, wantZones) {
+		t.Errorf("expected %v but got %v", wantZones, zones)
+	}
+}
@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	setOperationUnion        = "union"
+	setOperationIntersection = "intersection"
+	setOperationDifference   = "difference"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &SetDataSource{}
+
+func NewSetDataSource() datasource.DataSource {
+	return &SetDataSource{}
+}
+
+// SetDataSource defines the data source implementation.
+type SetDataSource struct {
+	client *listsClient
+}
+
+// SetDataSourceModel describes the data source data model.
+type SetDataSourceModel struct {
+	Operation types.String    `tfsdk:"operation"`
+	Inputs    []SetInputModel `tfsdk:"inputs"`
+	SplitAF   types.Bool      `tfsdk:"split_af"`
+	List      types.List      `tfsdk:"list"`
+	List4     types.List      `tfsdk:"list4"`
+	List6     types.List      `tfsdk:"list6"`
+	ID        types.String    `tfsdk:"id"`
+}
+
+// SetInputModel describes an operand of the set operation.
+type SetInputModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Filter   types.Map    `tfsdk:"filter"`
+	List     types.Set    `tfsdk:"list"`
+}
+
+func (d *SetDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_set"
+}
+
+func (d *SetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Computes the union, intersection or difference of the address space of NetBox Lists lists " +
+			"and literal lists. The result is a list of normalized, aggregated prefixes.",
+
+		Attributes: map[string]schema.Attribute{
+			"operation": schema.StringAttribute{
+				MarkdownDescription: "The set operation. One of `" + setOperationUnion + "`, `" + setOperationIntersection +
+					"` or `" + setOperationDifference + "`. " +
+					"For `" + setOperationDifference + "`, the address space of all other inputs is removed from the first input.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(setOperationUnion, setOperationIntersection, setOperationDifference),
+				},
+			},
+			"inputs": schema.ListNestedAttribute{
+				MarkdownDescription: "The operands. Each must have either `endpoint` or `list` set. " +
+					"Lists are fetched from NetBox concurrently.",
+				Required: true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"endpoint": schema.StringAttribute{
+							MarkdownDescription: "Lists endpoint.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("list")),
+							},
+						},
+						"filter": schema.MapAttribute{
+							MarkdownDescription: "Filters for the endpoint.",
+							Optional:            true,
+							ElementType:         types.SetType{ElemType: types.StringType},
+						},
+						"list": schema.SetAttribute{
+							MarkdownDescription: "Literal list of IP addresses/prefixes. Address ranges (`start-end`) are converted to the minimal list of prefixes covering them.",
+							Optional:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"split_af": schema.BoolAttribute{
+				MarkdownDescription: "Populate `list4` and `list6` with the IPv4 and IPv6 prefixes from `list`.",
+				Optional:            true,
+			},
+			"list": schema.ListAttribute{
+				MarkdownDescription: "List of prefixes.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"list4": schema.ListAttribute{
+				MarkdownDescription: "List of IPv4 prefixes if `split_af` is `true`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"list6": schema.ListAttribute{
+				MarkdownDescription: "List of IPv6 prefixes if `split_af` is `true`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Deterministic ID derived from `operation` and `list`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SetDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*listsClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *listsClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SetDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inputs := make([][]netip.Prefix, len(data.Inputs))
+	lists := make([][]string, len(data.Inputs))
+	errs := make([]error, len(data.Inputs))
+
+	var wg sync.WaitGroup
+	for i, in := range data.Inputs {
+		if !in.List.IsNull() {
+			var list []string
+			resp.Diagnostics.Append(in.List.ElementsAs(ctx, &list, false)...)
+			inputs[i] = parseSetInput(list, path.Root("inputs").AtListIndex(i).AtName("list"), &resp.Diagnostics)
+			continue
+		}
+
+		filter := map[string][]string{}
+		resp.Diagnostics.Append(in.Filter.ElementsAs(ctx, &filter, false)...)
+
+		wg.Add(1)
+		go func(i int, endpoint string, filter map[string][]string) {
+			defer wg.Done()
+			lists[i], errs[i] = d.client.get(ctx, endpoint, filter)
+		}(i, in.Endpoint.ValueString(), filter)
+	}
+	wg.Wait()
+
+	for i, in := range data.Inputs {
+		if errs[i] != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("inputs").AtListIndex(i),
+				"Error getting list",
+				fmt.Sprintf("Error getting list from %q: %v", in.Endpoint.ValueString(), errs[i]),
+			)
+			continue
+		}
+		if !in.List.IsNull() {
+			continue
+		}
+		tflog.Debug(ctx, "received list", map[string]interface{}{"endpoint": in.Endpoint.ValueString(), "count": len(lists[i])})
+		inputs[i] = parseSetInput(lists[i], path.Root("inputs").AtListIndex(i), &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result := computeSet(data.Operation.ValueString(), inputs)
+
+	var diag diag.Diagnostics
+	data.List, diag = types.ListValueFrom(ctx, types.StringType, prefixStrings(result))
+	resp.Diagnostics.Append(diag...)
+
+	if data.SplitAF.ValueBool() {
+		list4 := []string{}
+		list6 := []string{}
+		for _, p := range result {
+			if p.Addr().Is4() {
+				list4 = append(list4, p.String())
+			} else {
+				list6 = append(list6, p.String())
+			}
+		}
+		data.List4, diag = types.ListValueFrom(ctx, types.StringType, list4)
+		resp.Diagnostics.Append(diag...)
+		data.List6, diag = types.ListValueFrom(ctx, types.StringType, list6)
+		resp.Diagnostics.Append(diag...)
+	} else {
+		data.List4 = types.ListNull(types.StringType)
+		data.List6 = types.ListNull(types.StringType)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(listFingerprint(append([]string{data.Operation.ValueString()}, prefixStrings(result)...)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseSetInput parses each element of list as an IP address, prefix or range.
+// Errors are added to diags against attr.
+func parseSetInput(list []string, attr path.Path, diags *diag.Diagnostics) []netip.Prefix {
+	entries, err := parseEntries(list)
+	if err != nil {
+		var pe *parseEntryError
+		if errors.As(err, &pe) {
+			diags.AddAttributeError(attr, pe.summary, pe.detail)
+		} else {
+			diags.AddAttributeError(attr, "Error parsing list", err.Error())
+		}
+		return nil
+	}
+
+	ret := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, e.prefix)
+	}
+	return ret
+}
+
+// computeSet applies op to inputs and returns the aggregated result.
+func computeSet(op string, inputs [][]netip.Prefix) []netip.Prefix {
+	if len(inputs) == 0 {
+		return []netip.Prefix{}
+	}
+
+	switch op {
+	case setOperationIntersection:
+		ret := aggregatePrefixes(inputs[0])
+		for _, in := range inputs[1:] {
+			ret = intersectPrefixes(ret, in)
+		}
+		return ret
+	case setOperationDifference:
+		var exclude []netip.Prefix
+		for _, in := range inputs[1:] {
+			exclude = append(exclude, in...)
+		}
+		return aggregatePrefixes(subtractPrefixes(inputs[0], exclude))
+	default:
+		var all []netip.Prefix
+		for _, in := range inputs {
+			all = append(all, in...)
+		}
+		return aggregatePrefixes(all)
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestSetDataSource(t *testing.T) {
+	token := "abcdefghijklmnop"
+	h := newTestListsHandler(t, token)
+	h.addList("ip-addresses", map[string][]string{"tag": {"monitoring"}}, []string{"192.0.2.0/25", "2001:db8::1/128"})
+	h.addList("ip-addresses", map[string][]string{"tag": {"bastions"}}, []string{"192.0.2.128/25"})
+	h.addList("ip-addresses", map[string][]string{"tag": {"decommissioned"}}, []string{"192.0.2.5/32"})
+	h.addList("ip-ranges", map[string][]string{"tag": {"dhcp"}}, []string{"192.0.2.10-192.0.2.20"})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	providerConf := fmt.Sprintf(`
+provider "nblists" {
+    url = "%s"
+	token = "%s"
+}
+`, s.URL, token)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_set" "union" {
+	operation = "union"
+	inputs = [
+		{ endpoint = "ip-addresses", filter = { tag = ["monitoring"] } },
+		{ endpoint = "ip-addresses", filter = { tag = ["bastions"] } },
+	]
+	split_af = true
+}
+
+data "nblists_set" "difference" {
+	operation = "difference"
+	inputs = [
+		{ list = data.nblists_set.union.list4 },
+		{ endpoint = "ip-addresses", filter = { tag = ["decommissioned"] } },
+	]
+}
+
+data "nblists_set" "intersection" {
+	operation = "intersection"
+	inputs = [
+		{ endpoint = "ip-addresses", filter = { tag = ["monitoring"] } },
+		{ list = ["192.0.2.64/26", "198.51.100.0/24"] },
+	]
+}
+
+data "nblists_set" "ranges" {
+	operation = "union"
+	inputs = [
+		{ endpoint = "ip-ranges", filter = { tag = ["dhcp"] } },
+		{ list = ["198.51.100.0-198.51.100.255"] },
+	]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list.#", "2"),
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list.0", "192.0.2.0/24"),
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list.1", "2001:db8::1/128"),
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list4.#", "1"),
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list4.0", "192.0.2.0/24"),
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list6.#", "1"),
+					resource.TestCheckResourceAttr("data.nblists_set.union", "list6.0", "2001:db8::1/128"),
+
+					resource.TestCheckResourceAttr("data.nblists_set.difference", "list.#", "8"),
+					resource.TestCheckResourceAttr("data.nblists_set.difference", "list.0", "192.0.2.0/30"),
+					resource.TestCheckResourceAttr("data.nblists_set.difference", "list.1", "192.0.2.4/32"),
+					resource.TestCheckResourceAttr("data.nblists_set.difference", "list.2", "192.0.2.6/31"),
+					resource.TestCheckResourceAttr("data.nblists_set.difference", "list.7", "192.0.2.128/25"),
+					resource.TestCheckNoResourceAttr("data.nblists_set.difference", "list4.#"),
+
+					resource.TestCheckResourceAttr("data.nblists_set.intersection", "list.#", "1"),
+					resource.TestCheckResourceAttr("data.nblists_set.intersection", "list.0", "192.0.2.64/26"),
+
+					resource.TestCheckResourceAttr("data.nblists_set.ranges", "list.#", "5"),
+					resource.TestCheckResourceAttr("data.nblists_set.ranges", "list.0", "192.0.2.10/31"),
+					resource.TestCheckResourceAttr("data.nblists_set.ranges", "list.1", "192.0.2.12/30"),
+					resource.TestCheckResourceAttr("data.nblists_set.ranges", "list.2", "192.0.2.16/30"),
+					resource.TestCheckResourceAttr("data.nblists_set.ranges", "list.3", "192.0.2.20/32"),
+					resource.TestCheckResourceAttr("data.nblists_set.ranges", "list.4", "198.51.100.0/24"),
+				),
+			},
+		},
+	})
+
+	// endpoint and list are mutually exclusive
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_set" "test" {
+	operation = "union"
+	inputs = [
+		{ endpoint = "ip-addresses", list = ["192.0.2.1"] },
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+
+	// invalid range
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConf + `
+data "nblists_set" "test" {
+	operation = "union"
+	inputs = [
+		{ list = ["192.0.2.20-192.0.2.10"] },
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`Error parsing IP range`),
+			},
+		},
+	})
+}
+
+func TestComputeSet(t *testing.T) {
+	tests := map[string]struct {
+		op     string
+		inputs [][]string
+		want   []string
+	}{
+		"no inputs": {
+			op:   setOperationUnion,
+			want: []string{},
+		},
+		"union": {
+			op:     setOperationUnion,
+			inputs: [][]string{{"192.0.2.0/25", "2001:db8::/32"}, {"192.0.2.128/25"}},
+			want:   []string{"192.0.2.0/24", "2001:db8::/32"},
+		},
+		"intersection": {
+			op:     setOperationIntersection,
+			inputs: [][]string{{"192.0.2.0/24"}, {"192.0.2.0/25", "198.51.100.0/24"}, {"192.0.2.64/26"}},
+			want:   []string{"192.0.2.64/26"},
+		},
+		"difference": {
+			op:     setOperationDifference,
+			inputs: [][]string{{"192.0.2.0/24"}, {"192.0.2.0/25"}, {"192.0.2.128/26"}},
+			want:   []string{"192.0.2.192/26"},
+		},
+		"difference of one input": {
+			op:     setOperationDifference,
+			inputs: [][]string{{"192.0.2.0/25", "192.0.2.128/25"}},
+			want:   []string{"192.0.2.0/24"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			inputs := make([][]netip.Prefix, 0, len(tc.inputs))
+			for _, in := range tc.inputs {
+				inputs = append(inputs, mustParsePrefixes(t, in))
+			}
+			have := prefixStrings(computeSet(tc.op, inputs))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
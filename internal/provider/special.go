@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"net/netip"
+	"sort"
+)
+
+// Special-purpose address categories.
+const (
+	specialPrivate       = "private"
+	specialShared        = "shared"
+	specialLoopback      = "loopback"
+	specialLinkLocal     = "link_local"
+	specialMulticast     = "multicast"
+	specialDocumentation = "documentation"
+	specialULA           = "ula"
+	specialThisNetwork   = "this_network"
+	specialIETFProtocol  = "ietf_protocol"
+	specialBenchmarking  = "benchmarking"
+	specialReserved      = "reserved"
+	specialUnspecified   = "unspecified"
+	specialIPv4Mapped    = "ipv4_mapped"
+	specialTranslation   = "translation"
+	specialDiscard       = "discard"
+	specialSRv6          = "srv6"
+
+	// specialBogon matches every prefix in the registry.
+	specialBogon = "bogon"
+)
+
+// specialPrefix is an entry of the special-purpose address registry.
+type specialPrefix struct {
+	prefix   netip.Prefix
+	category string
+}
+
+// specialRegistry is based on the IANA IPv4 and IPv6 Special-Purpose
+// Address Registries, plus the multicast ranges. The AS112 and AMT anycast
+// prefixes are omitted on purpose since they are globally routed.
+var specialRegistry = []specialPrefix{
+	{netip.MustParsePrefix("0.0.0.0/8"), specialThisNetwork},         // RFC 791
+	{netip.MustParsePrefix("10.0.0.0/8"), specialPrivate},            // RFC 1918
+	{netip.MustParsePrefix("100.64.0.0/10"), specialShared},          // RFC 6598
+	{netip.MustParsePrefix("127.0.0.0/8"), specialLoopback},          // RFC 1122
+	{netip.MustParsePrefix("169.254.0.0/16"), specialLinkLocal},      // RFC 3927
+	{netip.MustParsePrefix("172.16.0.0/12"), specialPrivate},         // RFC 1918
+	{netip.MustParsePrefix("192.0.0.0/24"), specialIETFProtocol},     // RFC 6890
+	{netip.MustParsePrefix("192.0.2.0/24"), specialDocumentation},    // RFC 5737
+	{netip.MustParsePrefix("192.88.99.0/24"), specialTranslation},    // RFC 7526
+	{netip.MustParsePrefix("192.168.0.0/16"), specialPrivate},        // RFC 1918
+	{netip.MustParsePrefix("198.18.0.0/15"), specialBenchmarking},    // RFC 2544
+	{netip.MustParsePrefix("198.51.100.0/24"), specialDocumentation}, // RFC 5737
+	{netip.MustParsePrefix("203.0.113.0/24"), specialDocumentation},  // RFC 5737
+	{netip.MustParsePrefix("224.0.0.0/4"), specialMulticast},         // RFC 5771
+	{netip.MustParsePrefix("240.0.0.0/4"), specialReserved},          // RFC 1112
+	{netip.MustParsePrefix("::/128"), specialUnspecified},            // RFC 4291
+	{netip.MustParsePrefix("::1/128"), specialLoopback},              // RFC 4291
+	{netip.MustParsePrefix("::ffff:0:0/96"), specialIPv4Mapped},      // RFC 4291
+	{netip.MustParsePrefix("64:ff9b::/96"), specialTranslation},      // RFC 6052
+	{netip.MustParsePrefix("64:ff9b:1::/48"), specialTranslation},    // RFC 8215
+	{netip.MustParsePrefix("100::/64"), specialDiscard},              // RFC 6666
+	{netip.MustParsePrefix("2001::/23"), specialIETFProtocol},        // RFC 2928
+	{netip.MustParsePrefix("2001:db8::/32"), specialDocumentation},   // RFC 3849
+	{netip.MustParsePrefix("2002::/16"), specialTranslation},         // RFC 3056
+	{netip.MustParsePrefix("3fff::/20"), specialDocumentation},       // RFC 9637
+	{netip.MustParsePrefix("5f00::/16"), specialSRv6},                // RFC 9602
+	{netip.MustParsePrefix("fc00::/7"), specialULA},                  // RFC 4193
+	{netip.MustParsePrefix("fe80::/10"), specialLinkLocal},           // RFC 4291
+	{netip.MustParsePrefix("ff00::/8"), specialMulticast},            // RFC 4291
+}
+
+// specialCategories returns all special-purpose categories, sorted.
+func specialCategories() []string {
+	seen := map[string]bool{specialBogon: true}
+	for _, s := range specialRegistry {
+		seen[s.category] = true
+	}
+	ret := make([]string, 0, len(seen))
+	for c := range seen {
+		ret = append(ret, c)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// specialPrefixes returns the registry prefixes of the given categories.
+func specialPrefixes(categories []string) []netip.Prefix {
+	want := map[string]bool{}
+	for _, c := range categories {
+		want[c] = true
+	}
+	var ret []netip.Prefix
+	for _, s := range specialRegistry {
+		if want[specialBogon] || want[s.category] {
+			ret = append(ret, s.prefix)
+		}
+	}
+	return ret
+}
+
+// classifySpecial returns the entries overlapping each special-purpose category.
+// Categories without entries are omitted.
+func classifySpecial(entries []listEntry) map[string][]string {
+	ret := map[string][]string{}
+	for _, e := range entries {
+		matched := map[string]bool{}
+		for _, s := range specialRegistry {
+			if !matched[s.category] && s.prefix.Overlaps(e.prefix) {
+				matched[s.category] = true
+				ret[s.category] = append(ret[s.category], e.String())
+			}
+		}
+		if len(matched) > 0 {
+			ret[specialBogon] = append(ret[specialBogon], e.String())
+		}
+	}
+	for _, l := range ret {
+		sort.Strings(l)
+	}
+	return ret
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpecialCategories(t *testing.T) {
+	want := []string{
+		"benchmarking",
+		"bogon",
+		"discard",
+		"documentation",
+		"ietf_protocol",
+		"ipv4_mapped",
+		"link_local",
+		"loopback",
+		"multicast",
+		"private",
+		"reserved",
+		"shared",
+		"srv6",
+		"this_network",
+		"translation",
+		"ula",
+		"unspecified",
+	}
+	if have := specialCategories(); !reflect.DeepEqual(have, want) {
+		t.Errorf("got %v, want %v", have, want)
+	}
+}
+
+func TestClassifySpecial(t *testing.T) {
+	entries, err := parseEntries([]string{
+		"8.8.8.8/32",
+		"10.1.2.3",
+		"100.64.1.0/24",
+		"192.0.2.0/24",
+		"fd00::1",
+		"2606:4700::/32",
+		"10.0.0.0/7",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	want := map[string][]string{
+		specialPrivate:       {"10.0.0.0/7", "10.1.2.3"},
+		specialShared:        {"100.64.1.0/24"},
+		specialDocumentation: {"192.0.2.0/24"},
+		specialULA:           {"fd00::1"},
+		specialBogon:         {"10.0.0.0/7", "10.1.2.3", "100.64.1.0/24", "192.0.2.0/24", "fd00::1"},
+	}
+	if have := classifySpecial(entries); !reflect.DeepEqual(have, want) {
+		t.Errorf("got %v, want %v", have, want)
+	}
+}
+
+func TestSpecialPrefixes(t *testing.T) {
+	tests := map[string]struct {
+		categories []string
+		want       []string
+	}{
+		"none": {
+			categories: []string{},
+			want:       []string{},
+		},
+		"private": {
+			categories: []string{specialPrivate},
+			want:       []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
+		},
+		"translation": {
+			categories: []string{specialTranslation},
+			want:       []string{"192.88.99.0/24", "64:ff9b::/96", "64:ff9b:1::/48", "2002::/16"},
+		},
+		"link_local and ula": {
+			categories: []string{specialLinkLocal, specialULA},
+			want:       []string{"169.254.0.0/16", "fc00::/7", "fe80::/10"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := prefixStrings(specialPrefixes(tc.categories))
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got %v, want %v", have, tc.want)
+			}
+		})
+	}
+
+	if have, want := len(specialPrefixes([]string{specialBogon})), len(specialRegistry); have != want {
+		t.Errorf("got %d bogon prefixes, want %d", have, want)
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"math/big"
+	"net/netip"
+)
+
+// listStats accumulates address-space statistics of entries.
+type listStats struct {
+	count4    int
+	count6    int
+	prefixes4 []netip.Prefix
+	prefixes6 []netip.Prefix
+
+	// largest and smallest are the entries with the most and fewest addresses.
+	// The first entry wins ties.
+	largest      listEntry
+	largestSize  *big.Int
+	smallest     listEntry
+	smallestSize *big.Int
+}
+
+// add adds e to the statistics.
+func (s *listStats) add(e listEntry) {
+	if e.prefix.Addr().Is4() {
+		s.count4++
+		s.prefixes4 = append(s.prefixes4, e.prefix)
+	} else {
+		s.count6++
+		s.prefixes6 = append(s.prefixes6, e.prefix)
+	}
+
+	n := numAddresses(e.prefix)
+	if s.largestSize == nil || n.Cmp(s.largestSize) > 0 {
+		s.largest, s.largestSize = e, n
+	}
+	if s.smallestSize == nil || n.Cmp(s.smallestSize) < 0 {
+		s.smallest, s.smallestSize = e, n
+	}
+}
+
+// empty returns true if no entries were added.
+func (s *listStats) empty() bool {
+	return s.largestSize == nil
+}
+
+// totalAddresses4 returns the number of unique IPv4 addresses.
+func (s *listStats) totalAddresses4() *big.Int {
+	return totalAddresses(s.prefixes4)
+}
+
+// totalAddresses6 returns the number of unique IPv6 addresses.
+func (s *listStats) totalAddresses6() *big.Int {
+	return totalAddresses(s.prefixes6)
+}
+
+// totalAddresses returns the number of unique addresses in prefixes.
+func totalAddresses(prefixes []netip.Prefix) *big.Int {
+	total := new(big.Int)
+	for _, p := range aggregatePrefixes(prefixes) {
+		total.Add(total, numAddresses(p))
+	}
+	return total
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestListStats(t *testing.T) {
+	tests := map[string]struct {
+		in           []string
+		wantCount4   int
+		wantCount6   int
+		wantTotal4   string
+		wantTotal6   string
+		wantLargest  string
+		wantSmallest string
+		wantEmpty    bool
+	}{
+		"empty": {
+			in:         []string{},
+			wantTotal4: "0",
+			wantTotal6: "0",
+			wantEmpty:  true,
+		},
+		"overlapping entries are counted once": {
+			in:           []string{"192.0.2.0/24", "192.0.2.1", "192.0.2.128/25", "198.51.100.0/30"},
+			wantCount4:   4,
+			wantTotal4:   "260",
+			wantTotal6:   "0",
+			wantLargest:  "192.0.2.0/24",
+			wantSmallest: "192.0.2.1",
+		},
+		"mixed families": {
+			in:           []string{"10.0.0.0/8", "2001:db8::/64", "2001:db8::1", "192.0.2.1/32"},
+			wantCount4:   2,
+			wantCount6:   2,
+			wantTotal4:   "16777217",
+			wantTotal6:   "18446744073709551616",
+			wantLargest:  "2001:db8::/64",
+			wantSmallest: "2001:db8::1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			var s listStats
+			for _, e := range entries {
+				s.add(e)
+			}
+			if s.count4 != tc.wantCount4 || s.count6 != tc.wantCount6 {
+				t.Errorf("got %d IPv4 and %d IPv6 entries, want %d and %d", s.count4, s.count6, tc.wantCount4, tc.wantCount6)
+			}
+			if have := s.totalAddresses4().String(); have != tc.wantTotal4 {
+				t.Errorf("got %s IPv4 addresses, want %s", have, tc.wantTotal4)
+			}
+			if have := s.totalAddresses6().String(); have != tc.wantTotal6 {
+				t.Errorf("got %s IPv6 addresses, want %s", have, tc.wantTotal6)
+			}
+			if s.empty() != tc.wantEmpty {
+				t.Fatalf("got empty=%v, want %v", s.empty(), tc.wantEmpty)
+			}
+			if tc.wantEmpty {
+				return
+			}
+			if have := s.largest.String(); have != tc.wantLargest {
+				t.Errorf("got largest %s, want %s", have, tc.wantLargest)
+			}
+			if have := s.smallest.String(); have != tc.wantSmallest {
+				t.Errorf("got smallest %s, want %s", have, tc.wantSmallest)
+			}
+		})
+	}
+}
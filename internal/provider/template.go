@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the functions available in templates.
+// Each function takes an IP address or prefix.
+var templateFuncs = template.FuncMap{
+	"family": func(s string) (int, error) {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return 0, err
+		}
+		if p.Addr().Is4() {
+			return 4, nil
+		}
+		return 6, nil
+	},
+	"address": func(s string) (string, error) {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return "", err
+		}
+		return p.Addr().String(), nil
+	},
+	"prefixlen": func(s string) (int, error) {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return 0, err
+		}
+		return p.Bits(), nil
+	},
+	"mask": func(s string) (string, error) {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return "", err
+		}
+		return prefixNetmask(p).String(), nil
+	},
+	"wildcard": func(s string) (string, error) {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return "", err
+		}
+		return prefixHostmask(p).String(), nil
+	},
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+}
+
+// templateData is the data templates are executed with.
+type templateData struct {
+	List  []string
+	List4 []string
+	List6 []string
+}
+
+// parseTemplate parses text as a template with templateFuncs.
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("template").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// renderTemplate executes the template text with entries.
+func renderTemplate(text string, entries []listEntry) (string, error) {
+	t, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	data := templateData{List: entryStrings(entries), List4: []string{}, List6: []string{}}
+	for _, e := range entries {
+		if e.prefix.Addr().Is4() {
+			data.List4 = append(data.List4, e.String())
+		} else {
+			data.List6 = append(data.List6, e.String())
+		}
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
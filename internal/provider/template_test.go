@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := map[string]struct {
+		template  string
+		in        []string
+		want      string
+		wantError bool
+	}{
+		"families": {
+			template: `{{range .List4}}v4 {{.}}{{"\n"}}{{end}}{{range .List6}}v6 {{.}}{{"\n"}}{{end}}`,
+			in:       []string{"192.0.2.0/24", "2001:db8::1", "192.0.2.1"},
+			want:     "v4 192.0.2.0/24\nv4 192.0.2.1\nv6 2001:db8::1\n",
+		},
+		"functions": {
+			template: `{{range .List}}{{address .}},{{prefixlen .}},{{mask .}},{{wildcard .}},{{family .}};{{end}}`,
+			in:       []string{"192.0.2.0/24", "2001:db8::/32"},
+			want:     "192.0.2.0,24,255.255.255.0,0.0.0.255,4;2001:db8::,32,ffff:ffff::,::ffff:ffff:ffff:ffff:ffff:ffff,6;",
+		},
+		"join": {
+			template: `{{join ", " .List}}`,
+			in:       []string{"192.0.2.1", "192.0.2.2"},
+			want:     "192.0.2.1, 192.0.2.2",
+		},
+		"empty": {
+			template: `{{if not .List}}deny all{{end}}`,
+			in:       []string{},
+			want:     "deny all",
+		},
+		"syntax error": {
+			template:  `{{range .List}`,
+			wantError: true,
+		},
+		"invalid argument": {
+			template:  `{{address "not an address"}}`,
+			wantError: true,
+		},
+		"unknown field": {
+			template:  `{{.Missing}}`,
+			wantError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseEntries(tc.in)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			have, err := renderTemplate(tc.template, entries)
+			if err == nil && tc.wantError {
+				t.Fatalf("expected an error")
+			} else if err != nil && !tc.wantError {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if have != tc.want {
+				t.Errorf("got %q, want %q", have, tc.want)
+			}
+		})
+	}
+}